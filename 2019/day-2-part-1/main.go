@@ -80,79 +80,9 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
-	"strconv"
-	"strings"
-)
-
-type IntCode struct {
-	code     []int
-	position int
-}
-
-func NewIntCode(commaSeparated string) IntCode {
-	split := strings.Split(commaSeparated, ",")
-	code := make([]int, len(split))
-
-	for i, str := range split {
-		num, err := strconv.Atoi(str)
-		if err != nil {
-			log.Fatal(err)
-		}
-		code[i] = num
-	}
-
-	return IntCode{code, 0}
-}
-
-func (intCode *IntCode) Get(position int) int {
-	return intCode.code[position]
-}
-
-func (intCode *IntCode) Set(position int, value int) {
-	intCode.code[position] = value
-}
-
-func (intCode *IntCode) GetSlice(start int, length int) []int {
-	return intCode.code[start:(start + length)]
-}
-
-func (intCode *IntCode) String() string {
-	str := make([]string, len(intCode.code))
-	for i, code := range intCode.code {
-		str[i] = strconv.Itoa(code)
-	}
-	return strings.Join(str, ",")
-}
-
-func (intCode *IntCode) RunStep() (isDone bool) {
-	instruction := intCode.Get(intCode.position)
-	if instruction == 99 {
-		isDone = true
-	} else {
-		isDone = false
-		args := intCode.GetSlice(intCode.position+1, 3)
-		switch instruction {
-		case 1:
-			intCode.Set(args[2], intCode.Get(args[0])+intCode.Get(args[1]))
-			break
-		case 2:
-			intCode.Set(args[2], intCode.Get(args[0])*intCode.Get(args[1]))
-			break
-		default:
-			log.Fatalf("Invalid intcode instruction %v encountered", instruction)
-		}
-
-	}
 
-	intCode.position += 4
-	return
-}
-
-func (intCode *IntCode) Run() {
-	intCode.position = 0
-	for !intCode.RunStep() {
-	}
-}
+	"github.com/j6s/adventofcode/2019/intcode"
+)
 
 func main() {
 	lines, err := ioutil.ReadAll(os.Stdin)
@@ -160,13 +90,21 @@ func main() {
 		log.Fatal(err)
 	}
 
-	intCode := NewIntCode(string(lines))
+	intCode, err := intcode.NewIntCode(string(lines))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	intCode.CheckOverflow = true
 
 	// Restore intcode state
 	intCode.Set(1, 12)
 	intCode.Set(2, 2)
 
-	intCode.Run()
+	err = intCode.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	fmt.Print(intCode.Get(0))
 }
@@ -39,127 +39,66 @@
 package main
 
 import (
-	"errors"
+	"context"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
-	"strconv"
-	"strings"
-)
+	"time"
 
-type IntCode struct {
-	code               []int
-	instructionPointer int
-}
+	"github.com/j6s/adventofcode/2019/intcode"
+)
 
-func NewIntCode(commaSeparated string) IntCode {
-	split := strings.Split(commaSeparated, ",")
-	code := make([]int, len(split))
+func main() {
+	all := flag.Bool("all", false, "print every noun & verb producing the result instead of only the first one")
+	symbolic := flag.Bool("symbolic", false, "solve for noun & verb symbolically instead of trying every combination")
+	timeout := flag.Duration("timeout", time.Minute, "give up searching after this long")
+	maxSteps := flag.Int("max-steps", 100000, "number of instructions after which a single run is given up")
+	flag.Parse()
 
-	for i, str := range split {
-		num, err := strconv.Atoi(str)
-		if err != nil {
-			log.Fatal(err)
-		}
-		code[i] = num
+	lines, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	return IntCode{code, 0}
-}
-
-func (intCode *IntCode) Get(address int) int {
-	return intCode.code[address]
-}
-
-func (intCode *IntCode) Set(address int, value int) {
-	intCode.code[address] = value
-}
-
-func (intCode *IntCode) Clone() IntCode {
-	code := make([]int, len(intCode.code))
-	copy(code, intCode.code)
-	return IntCode{code, intCode.instructionPointer}
-}
-
-func (intCode *IntCode) GetSlice(start int, length int) []int {
-	return intCode.code[start:(start + length)]
-}
-
-func (intCode *IntCode) String() string {
-	str := make([]string, len(intCode.code))
-	for i, code := range intCode.code {
-		str[i] = strconv.Itoa(code)
+	desired := 19690720
+	intCode, err := intcode.NewIntCode(string(lines))
+	if err != nil {
+		log.Fatal(err)
 	}
-	return strings.Join(str, ",")
-}
-
-func (intCode *IntCode) RunStep() (isDone bool, err error) {
-	instruction := intCode.Get(intCode.instructionPointer)
-	if instruction == 99 {
-		isDone = true
-	} else {
-		isDone = false
-		param := intCode.GetSlice(intCode.instructionPointer+1, 3)
-		switch instruction {
-		case 1:
-			intCode.Set(param[2], intCode.Get(param[0])+intCode.Get(param[1]))
-			break
-		case 2:
-			intCode.Set(param[2], intCode.Get(param[0])*intCode.Get(param[1]))
-			break
-		default:
-			err = errors.New(fmt.Sprintf("Invalid intcode instruction %v encountered", instruction))
-		}
-
+	intCode.CheckOverflow = true
+
+	search := intcode.Search{
+		Addresses:     []int{1, 2},
+		Min:           0,
+		Max:           99,
+		ResultAddress: 0,
+		Target:        desired,
+		All:           *all,
+		MaxSteps:      *maxSteps,
 	}
-
-	intCode.instructionPointer += 4
-	return
-}
-
-func (intCode *IntCode) Run() error {
-	intCode.instructionPointer = 0
-
-	for true {
-		isDone, err := intCode.RunStep()
-		if err != nil {
-			return err
-		}
-		if isDone {
-			return nil
-		}
+	var matches [][]int
+	if *symbolic {
+		matches, err = search.Solve(intCode)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		matches, err = search.Run(ctx, intCode)
 	}
-
-	return errors.New("No exit instruction (99) encountered")
-}
-
-func main() {
-	lines, err := ioutil.ReadAll(os.Stdin)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if len(matches) == 0 {
+		fmt.Printf("Could not find noun & verb producing result %d\n", desired)
+		os.Exit(1)
+	}
 
-	desired := 19690720
-	intCode := NewIntCode(string(lines))
-
-	for noun := 0; noun <= 99; noun++ {
-		for verb := 0; verb <= 99; verb++ {
-			tempCode := intCode.Clone()
-			tempCode.Set(1, noun)
-			tempCode.Set(2, verb)
-			err := tempCode.Run()
-			if err != nil {
-				continue
-			}
-			result := tempCode.Get(0)
-			if result == desired {
-				fmt.Print(100*noun + verb)
-				os.Exit(0)
-			}
+	for i, match := range matches {
+		if i > 0 {
+			fmt.Println()
 		}
+		noun, verb := match[0], match[1]
+		fmt.Print(100*noun + verb)
 	}
-
-	fmt.Printf("Could not find noun & verb producing result %d\n", desired)
-	os.Exit(1)
 }
@@ -80,146 +80,13 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"math"
 	"os"
-	"strconv"
-	"strings"
-)
-
-type IntCode struct {
-	code               []int
-	instructionPointer int
-
-	inputBuffer int
-	Debug       bool
-}
-
-func NewIntCode(commaSeparated string) IntCode {
-	split := strings.Split(commaSeparated, ",")
-	code := make([]int, len(split))
 
-	for i, str := range split {
-		num, err := strconv.Atoi(str)
-		if err != nil {
-			log.Fatal(err)
-		}
-		code[i] = num
-	}
-
-	return IntCode{code: code}
-}
-
-func (intCode *IntCode) PrintDebug(additionalInfo string) {
-	if !intCode.Debug {
-		return
-	}
-	log.Printf(
-		"%s instructionPointer=%d inputBuffer=%d current=%v",
-		additionalInfo,
-		intCode.instructionPointer,
-		intCode.inputBuffer,
-		intCode.code[intCode.instructionPointer:intCode.instructionPointer+5],
-	)
-}
-
-func (intCode *IntCode) Get(address int) int {
-	return intCode.code[address]
-}
-
-func (intCode *IntCode) Set(address int, value int) {
-	intCode.code[address] = value
-}
-
-func (intCode *IntCode) getCurrentInstruction() (instruction int, paramModes int) {
-	instructionCode := intCode.Get(intCode.instructionPointer)
-	instruction = instructionCode % 100
-	paramModes = int(math.Floor(float64(instructionCode / 100)))
-	return
-}
-
-func (intCode *IntCode) parametersForCurrentInstruction(length int, paramModes int) (parameters []int, rawParameters []int) {
-	start := intCode.instructionPointer + 1
-	end := start + length
-
-	rawParameters = intCode.code[start:end]
-	parameters = make([]int, len(rawParameters))
-
-	for i, param := range rawParameters {
-		paramMode := paramModes / int(math.Pow10(i)) % 10
-		switch paramMode {
-		case 0:
-			// position mode
-			parameters[i] = intCode.Get(param)
-			break
-		case 1:
-			// immediate mode: nothing changes
-			parameters[i] = param
-			break
-		default:
-			log.Fatalf("Unknown parameter mode %d for parameter %d of intcode at position %d", paramMode, i, intCode.instructionPointer)
-		}
-	}
-
-	return
-}
-
-func (intCode *IntCode) RunStep() (isDone bool, err error) {
-	intCode.PrintDebug("")
-	instruction, paramModes := intCode.getCurrentInstruction()
-
-	var params, raw []int
-	isDone = false
-
-	switch instruction {
-	case 1:
-		params, raw = intCode.parametersForCurrentInstruction(3, paramModes)
-		intCode.Set(raw[2], params[0]+params[1])
-		break
-	case 2:
-		params, raw = intCode.parametersForCurrentInstruction(3, paramModes)
-		intCode.Set(raw[2], params[0]*params[1])
-		break
-	case 3:
-		params, raw = intCode.parametersForCurrentInstruction(1, paramModes)
-		intCode.Set(raw[0], intCode.inputBuffer)
-		break
-	case 4:
-		params, _ = intCode.parametersForCurrentInstruction(1, paramModes)
-		intCode.inputBuffer = params[0]
-	case 99:
-		isDone = true
-	default:
-		err = errors.New(fmt.Sprintf("Invalid intcode instruction %v encountered", instruction))
-	}
-
-	intCode.instructionPointer += len(params) + 1
-	return
-}
-
-func (intCode *IntCode) Run(input int) (int, error) {
-	intCode.inputBuffer = input
-	intCode.instructionPointer = 0
-
-	i := 1
-	for true {
-		isDone, err := intCode.RunStep()
-
-		if err != nil {
-			return intCode.inputBuffer, err
-		}
-		if isDone {
-			return intCode.inputBuffer, nil
-		}
-
-		i++
-	}
-
-	return intCode.inputBuffer, errors.New("No exit instruction (99) encountered")
-}
+	"github.com/j6s/adventofcode/2019/intcode"
+)
 
 func main() {
 	input, err := ioutil.ReadAll(os.Stdin)
@@ -227,12 +94,19 @@ func main() {
 		log.Fatal(err)
 	}
 
-	code := NewIntCode(string(input))
+	code, err := intcode.NewIntCode(string(input))
+	if err != nil {
+		log.Fatal(err)
+	}
+	code.CheckOverflow = true
 	// code.Debug = true
-	result, err := code.Run(1)
+	outputs, err := code.RunWithInput(1)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if len(outputs) == 0 {
+		log.Fatal("The program did not output a diagnostic code")
+	}
 
-	fmt.Println(result)
+	fmt.Println(outputs[len(outputs)-1])
 }
@@ -55,212 +55,13 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"math"
 	"os"
-	"strconv"
-	"strings"
-)
-
-type IntCode struct {
-	code               []int
-	instructionPointer int
-
-	inputBuffer int
-	Debug       bool
-}
-
-func NewIntCode(commaSeparated string) IntCode {
-	split := strings.Split(commaSeparated, ",")
-	code := make([]int, len(split))
-
-	for i, str := range split {
-		num, err := strconv.Atoi(str)
-		if err != nil {
-			log.Fatal(err)
-		}
-		code[i] = num
-	}
-
-	return IntCode{code: code}
-}
-
-func (intCode *IntCode) PrintDebug(additionalInfo string) {
-	if !intCode.Debug {
-		return
-	}
-
-	start := intCode.instructionPointer
-	end := int(math.Min(float64(len(intCode.code)-1), float64(intCode.instructionPointer+10)))
-	log.Printf(
-		"%s instructionPointer=%d inputBuffer=%d current=%v",
-		additionalInfo,
-		intCode.instructionPointer,
-		intCode.inputBuffer,
-		intCode.code[start:end],
-	)
-}
-
-func (intCode *IntCode) Get(address int) int {
-	return intCode.code[address]
-}
-
-func (intCode *IntCode) Set(address int, value int) {
-	intCode.code[address] = value
-}
-
-func (intCode *IntCode) getCurrentInstruction() (instruction int, paramModes int) {
-	instructionCode := intCode.Get(intCode.instructionPointer)
-	instruction = instructionCode % 100
-	paramModes = int(math.Floor(float64(instructionCode / 100)))
-	return
-}
-
-func (intCode *IntCode) parametersForCurrentInstruction(length int, paramModes int) (parameters []int, rawParameters []int) {
-	start := intCode.instructionPointer + 1
-	end := start + length
-
-	rawParameters = intCode.code[start:end]
-	parameters = make([]int, len(rawParameters))
-
-	for i, param := range rawParameters {
-		paramMode := paramModes / int(math.Pow10(i)) % 10
-		switch paramMode {
-		case 0:
-			// position mode
-			parameters[i] = intCode.Get(param)
-			break
-		case 1:
-			// immediate mode: nothing changes
-			parameters[i] = param
-			break
-		default:
-			log.Fatalf("Unknown parameter mode %d for parameter %d of intcode at position %d", paramMode, i, intCode.instructionPointer)
-		}
-	}
-
-	return
-}
-
-func (intCode *IntCode) incrementInstructionPointerBasedOnNumberOfParameters(params []int) {
-	intCode.instructionPointer += len(params) + 1
-}
-
-func (intCode *IntCode) RunStep() (isDone bool, err error) {
-	intCode.PrintDebug("")
-	instruction, paramModes := intCode.getCurrentInstruction()
-
-	// Note about params and raw: params are the parameters with the parameter mode applied to it, raw without.
-	// In most cases (e.g. calculation, comparison) you will want to use the params with parameter mode.
-	// Only use raw if you need to set an offset to update the intcode on the fly.
-	var params, raw []int
-	isDone = false
-
-	switch instruction {
-	case 1:
-		// Opcode 1 adds together numbers read from two positions and stores the result in a third position.
-		// The three integers immediately after the opcode tell you these three positions - the first two indicate
-		// the positions from which you should read the input values, and the third indicates the position at which
-		// the output should be stored.
-		params, raw = intCode.parametersForCurrentInstruction(3, paramModes)
-		intCode.Set(raw[2], params[0]+params[1])
-		intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
-		break
-	case 2:
-		// Opcode 2 works exactly like opcode 1, except it multiplies the two inputs instead of adding them.
-		// Again, the three integers after the opcode indicate where the inputs and outputs are, not their values.
-		params, raw = intCode.parametersForCurrentInstruction(3, paramModes)
-		intCode.Set(raw[2], params[0]*params[1])
-		intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
-		break
-	case 3:
-		// Opcode 3 takes a single integer as input and saves it to the position given by its only parameter.
-		// For example, the instruction 3,50 would take an input value and store it at address 50.
-		params, raw = intCode.parametersForCurrentInstruction(1, paramModes)
-		intCode.Set(raw[0], intCode.inputBuffer)
-		intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
-		break
-	case 4:
-		// Opcode 4 outputs the value of its only parameter. For example, the instruction 4,50 would output the value at address 50.
-		params, _ = intCode.parametersForCurrentInstruction(1, paramModes)
-		intCode.inputBuffer = params[0]
-		intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
-		break
-	case 5:
-		// Opcode 5 is jump-if-true: if the first parameter is non-zero, it sets the instruction pointer to the
-		// value from the second parameter. Otherwise, it does nothing.
-		params, _ = intCode.parametersForCurrentInstruction(2, paramModes)
-		if params[0] != 0 {
-			intCode.instructionPointer = params[1]
-		} else {
-			intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
-		}
-		break
-	case 6:
-		// Opcode 6 is jump-if-false: if the first parameter is zero, it sets the instruction pointer to the value
-		// from the second parameter. Otherwise, it does nothing.
-		params, _ = intCode.parametersForCurrentInstruction(2, paramModes)
-		if params[0] == 0 {
-			intCode.instructionPointer = params[1]
-		} else {
-			intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
-		}
-		break
-	case 7:
-		// Opcode 7 is less than: if the first parameter is less than the second parameter, it stores 1 in the position
-		// given by the third parameter. Otherwise, it stores 0.
-		params, raw = intCode.parametersForCurrentInstruction(3, paramModes)
-		if params[0] < params[1] {
-			intCode.Set(raw[2], 1)
-		} else {
-			intCode.Set(raw[2], 0)
-		}
-		intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
-		break
-	case 8:
-		// Opcode 8 is equals: if the first parameter is equal to the second parameter, it stores 1 in the position
-		// given by the third parameter. Otherwise, it stores 0.
-		params, raw = intCode.parametersForCurrentInstruction(3, paramModes)
-		if params[0] == params[1] {
-			intCode.Set(raw[2], 1)
-		} else {
-			intCode.Set(raw[2], 0)
-		}
-		intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
-		break
-	case 99:
-		// Opcode 99 terminates the program
-		isDone = true
-	default:
-		err = errors.New(fmt.Sprintf("Invalid intcode instruction %v encountered", instruction))
-	}
-
-	return
-}
 
-func (intCode *IntCode) Run(input int) (int, error) {
-	intCode.inputBuffer = input
-	intCode.instructionPointer = 0
-
-	i := 1
-	for true {
-		isDone, err := intCode.RunStep()
-
-		if err != nil {
-			return intCode.inputBuffer, err
-		}
-		if isDone {
-			return intCode.inputBuffer, nil
-		}
-
-		i++
-	}
-
-	return intCode.inputBuffer, errors.New("No exit instruction (99) encountered")
-}
+	"github.com/j6s/adventofcode/2019/intcode"
+)
 
 func main() {
 	input, err := ioutil.ReadAll(os.Stdin)
@@ -268,12 +69,19 @@ func main() {
 		log.Fatal(err)
 	}
 
-	code := NewIntCode(string(input))
+	code, err := intcode.NewIntCode(string(input))
+	if err != nil {
+		log.Fatal(err)
+	}
+	code.CheckOverflow = true
 	// code.Debug = true
-	result, err := code.Run(5)
+	outputs, err := code.RunWithInput(5)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if len(outputs) == 0 {
+		log.Fatal("The program did not output a diagnostic code")
+	}
 
-	fmt.Println(result)
+	fmt.Println(outputs[len(outputs)-1])
 }
@@ -129,8 +129,6 @@ func (object *SpaceObject) allPathsToAllOtherObjects() [][]*SpaceObject {
 
 		i++
 	}
-
-	return paths
 }
 
 // Depth-first search for a path to the destination.
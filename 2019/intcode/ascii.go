@@ -0,0 +1,95 @@
+package intcode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Values below this are characters for programs that speak ASCII, everything else is a plain number.
+const maxASCII = 128
+
+// Adapter for programs that speak ASCII: output characters are written to Writer as text, typed lines are read
+// from Reader and fed to the program character by character, including the trailing newline.
+type ASCII struct {
+	IntCode *IntCode
+	Reader  *bufio.Reader
+	Writer  io.Writer
+	// Output values that are not characters, such as the result of a program. They are also written to
+	// Writer on a line of their own.
+	Numbers []int
+}
+
+func NewASCII(intCode *IntCode, reader io.Reader, writer io.Writer) *ASCII {
+	return &ASCII{IntCode: intCode, Reader: bufio.NewReader(reader), Writer: writer}
+}
+
+// Converts text to input values.
+func EncodeASCII(text string) []int {
+	values := make([]int, 0, len(text))
+	for _, character := range []byte(text) {
+		values = append(values, int(character))
+	}
+	return values
+}
+
+// Converts output values to text, values that are not characters are returned separately.
+func DecodeASCII(values []int) (text string, numbers []int) {
+	builder := strings.Builder{}
+	for _, value := range values {
+		if value >= 0 && value < maxASCII {
+			builder.WriteByte(byte(value))
+		} else {
+			numbers = append(numbers, value)
+		}
+	}
+	return builder.String(), numbers
+}
+
+// Continues running the program, reading a line from Reader whenever it waits for input, until it halts.
+// The adapter passes values through the machine's queues, Input and Output are not used.
+func (ascii *ASCII) Run() error {
+	ascii.IntCode.Input = nil
+	ascii.IntCode.Output = nil
+
+	for {
+		state, err := ascii.IntCode.RunUntilBlocked()
+		writeErr := ascii.flush()
+		if err != nil {
+			return err
+		}
+		if writeErr != nil {
+			return writeErr
+		}
+		if state != WaitingForInput {
+			return nil
+		}
+
+		line, err := ascii.Reader.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return ErrInputClosed
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		ascii.IntCode.Feed(EncodeASCII(strings.TrimRight(line, "\r\n") + "\n")...)
+	}
+}
+
+// Writes everything the program output so far.
+func (ascii *ASCII) flush() error {
+	for _, value := range ascii.IntCode.TakeOutput() {
+		var err error
+		if value >= 0 && value < maxASCII {
+			_, err = ascii.Writer.Write([]byte{byte(value)})
+		} else {
+			ascii.Numbers = append(ascii.Numbers, value)
+			_, err = fmt.Fprintf(ascii.Writer, "%d\n", value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
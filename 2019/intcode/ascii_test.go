@@ -0,0 +1,43 @@
+package intcode
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestASCII(t *testing.T) {
+	text, numbers := DecodeASCII(append(EncodeASCII("Hello\n"), 1234))
+	if text != "Hello\n" || !reflect.DeepEqual(numbers, []int{1234}) {
+		t.Errorf("Expected Hello and 1234 but got %q and %v", text, numbers)
+	}
+
+	// echoes characters until it reads a newline, then outputs the number of characters before it
+	code, err := Assemble(`
+loop:   IN -> [char]
+        EQ [char], #10 -> [done]
+        JT [done], end
+        OUT [char]
+        ADD [count], #1 -> [count]
+        JT #1, loop
+end:    OUT [char]
+        OUT [count]
+        HALT
+char:   DATA 0
+done:   DATA 0
+count:  DATA 1000
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intCode := IntCode{code: newMemory(code)}
+	var output bytes.Buffer
+	ascii := NewASCII(&intCode, strings.NewReader("abc\n"), &output)
+	if err := ascii.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if output.String() != "abc\n1003\n" || !reflect.DeepEqual(ascii.Numbers, []int{1003}) {
+		t.Errorf("Expected abc and 1003 but got %q and %v", output.String(), ascii.Numbers)
+	}
+}
@@ -0,0 +1,238 @@
+package intcode
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	labelPattern      = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*):`)
+	addressPattern    = regexp.MustCompile(`^(-?[0-9]+)\s+`)
+	expressionPattern = regexp.MustCompile(`^(?:(-?[0-9]+)|([A-Za-z_][A-Za-z0-9_]*)\s*(?:([+-])\s*([0-9]+))?)$`)
+)
+
+// Returned by Assemble, Line is the 1-based line of the source the error was found in.
+type AssembleError struct {
+	Line    int
+	Message string
+}
+
+func (err *AssembleError) Error() string {
+	return fmt.Sprintf("Line %d: %s", err.Line, err.Message)
+}
+
+// A parsed source line that turns into values of the program.
+type assemblyStatement struct {
+	line     int
+	address  int
+	info     Instruction
+	opcode   int
+	operands []string
+	isData   bool
+}
+
+// Assembles a program written in the same notation the disassembler prints into intcode.
+//
+//	; comments run until the end of the line
+//	start:  IN -> [value]        ; [n] is position mode, #n immediate mode and [rb+n] relative mode
+//	        EQ [value], #8 -> [rb+1]
+//	        JT [rb+1], start     ; plain numbers or labels are immediate values, e.g. jump targets
+//	        OUT [value]
+//	        HALT
+//	value:  DATA 0
+//
+// Labels can be used in place of any number and may have an offset (value+1). Lines may start with the address
+// the statement is expected at, as printed by the disassembler, which is then verified.
+func Assemble(source string) ([]int, error) {
+	// First pass: find out where every statement and label ends up.
+	labels := make(map[string]int)
+	statements := make([]assemblyStatement, 0)
+	address := 0
+
+	for i, line := range strings.Split(source, "\n") {
+		lineNumber := i + 1
+		if comment := strings.Index(line, ";"); comment >= 0 {
+			line = line[:comment]
+		}
+		line = strings.TrimSpace(line)
+
+		if match := addressPattern.FindStringSubmatch(line); match != nil {
+			expected, _ := strconv.Atoi(match[1])
+			if expected != address {
+				return nil, &AssembleError{lineNumber, fmt.Sprintf("Statement is at address %d, not %d", address, expected)}
+			}
+			line = strings.TrimSpace(line[len(match[0]):])
+		}
+
+		for {
+			match := labelPattern.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			if _, exists := labels[match[1]]; exists {
+				return nil, &AssembleError{lineNumber, fmt.Sprintf("Label %s is defined twice", match[1])}
+			}
+			labels[match[1]] = address
+			line = strings.TrimSpace(line[len(match[0]):])
+		}
+
+		if line == "" {
+			continue
+		}
+
+		fields := []string{line}
+		if separator := strings.IndexAny(line, " \t"); separator >= 0 {
+			fields = []string{line[:separator], line[separator+1:]}
+		}
+		mnemonic := strings.ToUpper(fields[0])
+		operands := make([]string, 0)
+		if len(fields) > 1 {
+			for _, operand := range strings.Split(strings.Replace(fields[1], "->", ",", -1), ",") {
+				operand = strings.TrimSpace(operand)
+				if operand != "" {
+					operands = append(operands, operand)
+				}
+			}
+		}
+
+		statement := assemblyStatement{line: lineNumber, address: address, operands: operands}
+		if mnemonic == "DATA" {
+			statement.isData = true
+			address += len(operands)
+		} else {
+			opcode, known := LookupMnemonic(fields[0])
+			if !known {
+				return nil, &AssembleError{lineNumber, fmt.Sprintf("Unknown instruction %s", fields[0])}
+			}
+			statement.opcode = opcode
+			statement.info = instructionSet[opcode]
+			if len(operands) != statement.info.Parameters {
+				return nil, &AssembleError{lineNumber, fmt.Sprintf(
+					"%s takes %d parameters but %d were given",
+					statement.info.Name,
+					statement.info.Parameters,
+					len(operands),
+				)}
+			}
+			address += 1 + statement.info.Parameters
+		}
+		statements = append(statements, statement)
+	}
+
+	// Second pass: encode everything now that all labels are known.
+	code := make([]int, 0, address)
+	for _, statement := range statements {
+		values, err := statement.encode(labels)
+		if err != nil {
+			return nil, &AssembleError{statement.line, err.Error()}
+		}
+		code = append(code, values...)
+	}
+
+	return code, nil
+}
+
+func (statement assemblyStatement) encode(labels map[string]int) ([]int, error) {
+	if statement.isData {
+		values := make([]int, len(statement.operands))
+		for i, operand := range statement.operands {
+			value, err := evaluateExpression(operand, labels)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+
+	// Operands are written with the read parameters first and the written ones after the arrow, so they have to
+	// be sorted back into the order of the parameters.
+	order := make([]int, 0, statement.info.Parameters)
+	for i := 0; i < statement.info.Parameters; i++ {
+		if !statement.info.isWrite(i) {
+			order = append(order, i)
+		}
+	}
+	order = append(order, statement.info.Writes...)
+
+	values := make([]int, 1+statement.info.Parameters)
+	modes := 0
+	for i, operand := range statement.operands {
+		parameter := order[i]
+		mode, value, err := parseOperand(operand, labels)
+		if err != nil {
+			return nil, err
+		}
+		if mode == immediateMode && statement.info.isWrite(parameter) {
+			return nil, fmt.Errorf("Parameter %d of %s is written to and cannot be immediate", parameter, statement.info.Name)
+		}
+		values[1+parameter] = value
+		modes += mode * pow10(parameter)
+	}
+	values[0] = statement.opcode + modes*100
+
+	return values, nil
+}
+
+// Parses a single operand into its parameter mode and value.
+func parseOperand(operand string, labels map[string]int) (mode int, value int, err error) {
+	switch {
+	case strings.HasPrefix(operand, "#"):
+		mode = immediateMode
+		value, err = evaluateExpression(operand[1:], labels)
+	case strings.HasPrefix(operand, "[") && strings.HasSuffix(operand, "]"):
+		inner := strings.TrimSpace(operand[1 : len(operand)-1])
+		offset := strings.Replace(strings.TrimPrefix(inner, "rb"), " ", "", -1)
+		if strings.HasPrefix(inner, "rb") && (offset == "" || offset[0] == '+' || offset[0] == '-') {
+			mode = relativeMode
+			if offset == "" {
+				return mode, 0, nil
+			}
+			value, err = evaluateExpression(strings.TrimPrefix(offset, "+"), labels)
+		} else {
+			mode = positionMode
+			value, err = evaluateExpression(inner, labels)
+		}
+	default:
+		mode = immediateMode
+		value, err = evaluateExpression(operand, labels)
+	}
+	return
+}
+
+// Evaluates a number, a label or a label with an offset such as loop+2.
+func evaluateExpression(expression string, labels map[string]int) (int, error) {
+	match := expressionPattern.FindStringSubmatch(strings.TrimSpace(expression))
+	if match == nil {
+		return 0, fmt.Errorf("Invalid value %q", expression)
+	}
+	if match[1] != "" {
+		return strconv.Atoi(match[1])
+	}
+
+	value, exists := labels[match[2]]
+	if !exists {
+		return 0, fmt.Errorf("Unknown label %s", match[2])
+	}
+	if match[4] != "" {
+		offset, err := strconv.Atoi(match[4])
+		if err != nil {
+			return 0, err
+		}
+		if match[3] == "-" {
+			offset = -offset
+		}
+		value += offset
+	}
+	return value, nil
+}
+
+func pow10(exponent int) int {
+	result := 1
+	for i := 0; i < exponent; i++ {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package intcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssembleOperands(t *testing.T) {
+	source := `
+	IN	-> [rbuf]
+	ADD [rb], [rb + 2] -> [rb-1]
+	OUT	[rbuf]
+	HALT
+rbuf:	DATA 0
+`
+	code, err := Assemble(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{3, 9, 22201, 0, 2, -1, 4, 9, 99, 0}
+	if !reflect.DeepEqual(code, expected) {
+		t.Errorf("Expected %v but got %v", expected, code)
+	}
+}
+
+func TestAssembleRegisteredInstruction(t *testing.T) {
+	double := Instruction{
+		Name:       "dbl",
+		Parameters: 2,
+		Writes:     []int{1},
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			intCode.Write(raw[1], params[0]*2)
+			return Running, nil
+		},
+	}
+	if err := Register(42, double); err != nil {
+		t.Fatal(err)
+	}
+	defer delete(instructionSet, 42)
+
+	code, err := Assemble("DBL #21 -> [result]\nOUT [result]\nHALT\nresult: DATA 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intCode := IntCode{code: newMemory(code)}
+	outputs, err := intCode.RunWithInput()
+	if err != nil || !reflect.DeepEqual(outputs, []int{42}) {
+		t.Errorf("Expected [42] but got %v (%v)", outputs, err)
+	}
+}
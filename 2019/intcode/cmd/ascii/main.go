@@ -0,0 +1,51 @@
+/*
+ * Plays an intcode program that speaks ASCII from the shell: everything the program prints is shown as text
+ * and every typed line is sent to it as input.
+ *
+ *   go run ./cmd/ascii program.txt
+ *
+ * Lines from a file can be replayed before continuing interactively with -script.
+ */
+package main
+
+import (
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+func main() {
+	script := flag.String("script", "", "file with lines that are sent before reading from stdin")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("Usage: ascii [-script commands.txt] program.txt")
+	}
+
+	program, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	intCode, err := intcode.NewIntCode(string(program))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var input io.Reader = os.Stdin
+	if *script != "" {
+		file, err := os.Open(*script)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = io.MultiReader(file, os.Stdin)
+	}
+
+	err = intcode.NewASCII(&intCode, input, os.Stdout).Run()
+	if err != nil && err != intcode.ErrInputClosed {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,31 @@
+/*
+ * Assembles the intcode assembly read from stdin into a comma separated program, e.g.
+ *
+ *   go run ./cmd/asm < compare-to-8.asm
+ *
+ * The notation is the one printed by the disassembler, see intcode.Assemble for details.
+ */
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := intcode.Assemble(string(input))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(intcode.FormatProgram(code))
+}
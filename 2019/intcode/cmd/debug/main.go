@@ -0,0 +1,322 @@
+/*
+ * Interactive step debugger for intcode programs. The program is read from the file given as argument,
+ * commands are read from stdin:
+ *
+ *   go run ./cmd/debug -input 5 ../day-5-part-2/input.txt
+ *
+ * Type `help` for a list of commands.
+ */
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+// Largest number of instructions or memory cells a single command works on.
+const maxCount = 10000
+
+const help = `Commands:
+  s, step [n]             execute the next n instructions (default 1)
+  back [n]                undo the last n instructions (default 1)
+  who <address>           step back to the instruction that last wrote to address
+  goto <step>             move backwards or forwards to the given step number
+  c, continue             run until a breakpoint, watchpoint, halt or missing input
+  b, break <address>      break in front of the instruction at address
+  b, break op <opcode>    break in front of every instruction with the opcode or mnemonic
+  d, delete <address>     remove a breakpoint, 'delete op <opcode>' removes an opcode breakpoint
+  w, watch <address>      stop whenever the value at address changes
+  unwatch <address>       remove a watchpoint
+  x, mem <address> [n]    show n values of memory starting at address (default 8)
+  poke <address> <value>  overwrite the value at address
+  in, input <values...>   feed values to the input instruction
+  l, list [n]             disassemble the next n instructions (default 5)
+  i, info                 show registers, breakpoints and watchpoints
+  ops                     list the known instructions
+  save <file>             write a snapshot of the machine to file
+  load <file>             continue with the machine from a snapshot file
+  q, quit                 exit the debugger`
+
+func main() {
+	inputs := flag.String("input", "", "comma separated values fed to the program before starting")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("Usage: debug [-input 1,2,3] program.txt")
+	}
+
+	program, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	intCode, err := intcode.NewIntCode(string(program))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *inputs != "" {
+		values, err := intcode.ParseProgram(*inputs)
+		if err != nil {
+			log.Fatal(err)
+		}
+		intCode.Feed(values...)
+	}
+
+	debugger := intcode.NewDebugger(&intCode)
+	printCurrent(debugger)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Print("> ")
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 {
+			if fields[0] == "q" || fields[0] == "quit" {
+				return
+			}
+			err := runCommand(debugger, fields[0], fields[1:])
+			if err != nil {
+				fmt.Println(err)
+			}
+		}
+		fmt.Print("> ")
+	}
+}
+
+func runCommand(debugger *intcode.Debugger, command string, args []string) error {
+	intCode := debugger.IntCode
+
+	switch command {
+	case "s", "step":
+		count, err := optionalCount(args, 0, 1)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < count; i++ {
+			stop, err := debugger.Step()
+			printStop(debugger, stop, err)
+			if err != nil || stop.State != intcode.Running || len(stop.Watchpoints) > 0 {
+				break
+			}
+		}
+		printCurrent(debugger)
+	case "back":
+		count, err := optionalCount(args, 0, 1)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < count; i++ {
+			stop, err := debugger.StepBack()
+			if err != nil {
+				return err
+			}
+			for _, address := range stop.Watchpoints {
+				fmt.Printf("watchpoint: [%d] = %d\n", address, intCode.Get(address))
+			}
+		}
+		printCurrent(debugger)
+	case "who":
+		address, err := requiredNumber(args, 0)
+		if err != nil {
+			return err
+		}
+		event, err := debugger.RunBackToWrite(address)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("step %d wrote [%d]\n", event.Step, address)
+		printCurrent(debugger)
+	case "goto":
+		step, err := requiredNumber(args, 0)
+		if err != nil {
+			return err
+		}
+		state, err := debugger.GoToStep(step)
+		printStop(debugger, intcode.DebugStop{State: state}, err)
+		printCurrent(debugger)
+	case "c", "continue":
+		stop, err := debugger.Continue()
+		printStop(debugger, stop, err)
+		printCurrent(debugger)
+	case "b", "break", "d", "delete":
+		remove := command == "d" || command == "delete"
+		if len(args) == 2 && args[0] == "op" {
+			opcode, known := intcode.LookupMnemonic(args[1])
+			if !known {
+				var err error
+				opcode, err = strconv.Atoi(args[1])
+				if err != nil {
+					return fmt.Errorf("Unknown instruction %s", args[1])
+				}
+			}
+			if remove {
+				debugger.RemoveOpcodeBreakpoint(opcode)
+			} else {
+				debugger.AddOpcodeBreakpoint(opcode)
+			}
+			return nil
+		}
+		address, err := requiredNumber(args, 0)
+		if err != nil {
+			return err
+		}
+		if remove {
+			debugger.RemoveBreakpoint(address)
+		} else {
+			debugger.AddBreakpoint(address)
+		}
+	case "w", "watch", "unwatch":
+		address, err := requiredNumber(args, 0)
+		if err != nil {
+			return err
+		}
+		if command == "unwatch" {
+			debugger.RemoveWatchpoint(address)
+		} else {
+			debugger.AddWatchpoint(address)
+		}
+	case "x", "mem":
+		address, err := requiredNumber(args, 0)
+		if err != nil {
+			return err
+		}
+		count, err := optionalCount(args, 1, 8)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%6d  %s\n", address, intcode.FormatProgram(intCode.GetSlice(address, count)))
+	case "poke":
+		address, err := requiredNumber(args, 0)
+		if err != nil {
+			return err
+		}
+		value, err := requiredNumber(args, 1)
+		if err != nil {
+			return err
+		}
+		intCode.Set(address, value)
+	case "in", "input":
+		values, err := intcode.ParseProgram(strings.Join(args, ","))
+		if err != nil {
+			return err
+		}
+		intCode.Feed(values...)
+	case "l", "list":
+		count, err := optionalCount(args, 0, 5)
+		if err != nil {
+			return err
+		}
+		address := intCode.InstructionPointer()
+		for i := 0; i < count; i++ {
+			line := intCode.DisassembleAt(address)
+			fmt.Println(line)
+			address += len(line.Values)
+		}
+	case "i", "info":
+		fmt.Printf(
+			"state=%s step=%d instructionPointer=%d relativeBase=%d\n",
+			intCode.State(),
+			intCode.Steps(),
+			intCode.InstructionPointer(),
+			intCode.RelativeBase(),
+		)
+		fmt.Printf("breakpoints=%v opcodeBreakpoints=%v watchpoints=%v\n",
+			debugger.Breakpoints(),
+			debugger.OpcodeBreakpoints(),
+			debugger.Watchpoints(),
+		)
+		printCurrent(debugger)
+	case "ops":
+		for _, opcode := range intcode.Opcodes() {
+			instruction, _ := intcode.LookupInstruction(opcode)
+			fmt.Printf("%6d  %-6s parameters=%d writes=%v\n", opcode, instruction.Name, instruction.Parameters, instruction.Writes)
+		}
+	case "save":
+		if len(args) != 1 {
+			return fmt.Errorf("Usage: save <file>")
+		}
+		file, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return intCode.SaveSnapshot(file)
+	case "load":
+		if len(args) != 1 {
+			return fmt.Errorf("Usage: load <file>")
+		}
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		loaded, err := intcode.LoadSnapshot(file)
+		if err != nil {
+			return err
+		}
+		*intCode = loaded
+		intCode.RecordHistory()
+		for _, address := range debugger.Watchpoints() {
+			debugger.AddWatchpoint(address)
+		}
+		printCurrent(debugger)
+	case "h", "help":
+		fmt.Println(help)
+	default:
+		return fmt.Errorf("Unknown command %s, type help for a list of commands", command)
+	}
+
+	return nil
+}
+
+func printStop(debugger *intcode.Debugger, stop intcode.DebugStop, err error) {
+	for _, value := range debugger.IntCode.TakeOutput() {
+		fmt.Printf("output: %d\n", value)
+	}
+	for _, address := range stop.Watchpoints {
+		fmt.Printf("watchpoint: [%d] = %d\n", address, debugger.IntCode.Get(address))
+	}
+	if stop.Breakpoint {
+		fmt.Println("breakpoint")
+	}
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+	} else if stop.State != intcode.Running {
+		fmt.Println(stop.State)
+	}
+}
+
+func printCurrent(debugger *intcode.Debugger) {
+	fmt.Println(debugger.IntCode.DisassembleAt(debugger.IntCode.InstructionPointer()))
+}
+
+func requiredNumber(args []string, index int) (int, error) {
+	if index >= len(args) {
+		return 0, fmt.Errorf("Missing argument %d", index+1)
+	}
+	return strconv.Atoi(args[index])
+}
+
+func optionalNumber(args []string, index int, fallback int) (int, error) {
+	if index >= len(args) {
+		return fallback, nil
+	}
+	return strconv.Atoi(args[index])
+}
+
+// Like optionalNumber, but only accepts counts between 1 and maxCount.
+func optionalCount(args []string, index int, fallback int) (int, error) {
+	count, err := optionalNumber(args, index, fallback)
+	if err != nil {
+		return 0, err
+	}
+	if count < 1 || count > maxCount {
+		return 0, fmt.Errorf("Count must be between 1 and %d, got %d", maxCount, count)
+	}
+	return count, nil
+}
@@ -0,0 +1,35 @@
+/*
+ * Disassembles the intcode program read from stdin into a readable listing, e.g.
+ *
+ *   cat ../day-5-part-2/input.txt | go run ./cmd/disasm
+ *
+ * Instructions reachable from address 0 are decoded first, the rest is decoded wherever it forms valid
+ * instructions, which are marked with an `; unreached` comment. Instructions that are patched at runtime (day 5 writes its input into the instruction at
+ * address 6) are listed as data.
+ */
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := intcode.ParseProgram(string(input))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, line := range intcode.Disassemble(code) {
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Runs an intcode program and prints a profile of the executed instructions. Optionally every executed
+ * instruction is written to a trace file with one JSON object per line:
+ *
+ *   go run ./cmd/trace -input 5 -trace trace.jsonl ../day-5-part-2/input.txt
+ */
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+func main() {
+	inputs := flag.String("input", "", "comma separated values fed to the program")
+	traceFile := flag.String("trace", "", "file to write the trace of every executed instruction to")
+	top := flag.Int("top", 20, "number of most executed addresses to show")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("Usage: trace [-input 1,2,3] [-trace trace.jsonl] program.txt")
+	}
+
+	program, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	intCode, err := intcode.NewIntCode(string(program))
+	if err != nil {
+		log.Fatal(err)
+	}
+	values := make([]int, 0)
+	if *inputs != "" {
+		values, err = intcode.ParseProgram(*inputs)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	profile := intcode.NewProfile()
+	intCode.Tracer = profile
+
+	var jsonTracer *intcode.JSONTracer
+	if *traceFile != "" {
+		file, err := os.Create(*traceFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		jsonTracer = intcode.NewJSONTracer(file)
+		intCode.Tracer = intcode.MultiTracer(profile, jsonTracer)
+	}
+
+	outputs, runErr := intCode.RunWithInput(values...)
+	fmt.Printf("outputs: %v\n", outputs)
+	if runErr != nil {
+		fmt.Printf("error: %v\n", runErr)
+	}
+	if jsonTracer != nil && jsonTracer.Err() != nil {
+		log.Fatal(jsonTracer.Err())
+	}
+
+	fmt.Println()
+	err = profile.WriteSummary(os.Stdout, *top)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * Checks an intcode program for problems without running it, see intcode.Vet. The program is read from stdin
+ * and every finding is printed with the address it was found at:
+ *
+ *   go run ./cmd/vet < ../day-5-part-2/input.txt
+ *
+ * Exits with status 1 if any errors were found, warnings alone do not fail.
+ */
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := intcode.ParseProgram(string(input))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := false
+	for _, finding := range intcode.Vet(code) {
+		fmt.Println(finding)
+		if finding.Severity == intcode.Error {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Runs an intcode program that draws a 2D world and shows it. In arcade mode the program outputs x, y and tile
+ * triples and the joystick follows the ball, in robot mode it controls a hull painting robot:
+ *
+ *   go run ./cmd/world -mode arcade -free -live program.txt
+ *   go run ./cmd/world -mode robot -start 1 -png hull.png program.txt
+ *
+ * -gif records a frame whenever the arcade waits for input or the robot moves.
+ */
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+	"github.com/j6s/adventofcode/2019/intcode/world"
+)
+
+func main() {
+	mode := flag.String("mode", "arcade", "arcade or robot")
+	live := flag.Bool("live", false, "redraw the world in the terminal while the program runs")
+	delay := flag.Duration("delay", 20*time.Millisecond, "pause after every live frame")
+	pngFile := flag.String("png", "", "file to write the final world to as PNG")
+	gifFile := flag.String("gif", "", "file to write an animation of the world to as GIF")
+	scale := flag.Int("scale", 4, "pixels per tile in exported images")
+	free := flag.Bool("free", false, "arcade: play for free by setting address 0 to 2")
+	start := flag.Int("start", world.Black, "robot: color of the panel the robot starts on")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("Usage: world [-mode arcade|robot] [-live] [-png out.png] [-gif out.gif] program.txt")
+	}
+
+	program, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	intCode, err := intcode.NewIntCode(string(program))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	terminal := world.Terminal{Writer: os.Stdout, Delay: *delay}
+	recording := world.Recording{Scale: *scale, Delay: 5}
+	var result *world.World
+
+	switch *mode {
+	case "arcade":
+		terminal.Palette = world.ArcadePalette
+		recording.Colors = world.ArcadeColors
+		if *free {
+			intCode.Set(0, 2)
+		}
+
+		screen := world.NewScreen()
+		result = screen.World
+		err = screen.Run(&intCode, func(screen *world.Screen) []int {
+			if *live {
+				terminal.Draw(screen.World, fmt.Sprintf("score: %d", screen.Score))
+			}
+			if *gifFile != "" {
+				recording.Capture(screen.World)
+			}
+			return []int{screen.FollowBall()}
+		})
+		fmt.Printf("blocks: %d score: %d\n", screen.World.Count(world.Block), screen.Score)
+	case "robot":
+		terminal.Palette = world.HullPalette
+		recording.Colors = world.HullColors
+
+		robot := world.NewRobot()
+		robot.World.Set(robot.Position, *start)
+		result = robot.World
+		err = robot.Run(&intCode, func(robot *world.Robot) {
+			if *live {
+				terminal.Draw(robot.World, fmt.Sprintf("painted: %d", len(robot.Painted)))
+			}
+			if *gifFile != "" {
+				recording.Capture(robot.World)
+			}
+		})
+		fmt.Print(result.Render(world.HullPalette))
+		fmt.Printf("painted: %d\n", len(robot.Painted))
+	default:
+		log.Fatalf("Unknown mode %s", *mode)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *pngFile != "" {
+		writeFile(*pngFile, func(writer io.Writer) error {
+			return result.WritePNG(writer, recording.Colors, *scale)
+		})
+	}
+	if *gifFile != "" {
+		writeFile(*gifFile, recording.WriteGIF)
+	}
+}
+
+func writeFile(path string, write func(writer io.Writer) error) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	err = write(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,226 @@
+package intcode
+
+import "context"
+
+// An instruction that was decoded ahead of time. It executes the instruction on the machine and returns the
+// state the machine is in afterwards, just like IntCode.RunStep.
+type compiledInstruction func(intCode *IntCode) (State, error)
+
+// Reads a parameter in its mode, returns its value and the address it refers to, see ExecuteFunc.
+type compiledOperand func(intCode *IntCode) (value int, address int, err error)
+
+// CompiledProgram runs a machine without decoding the parameter modes of every executed instruction: each
+// instruction is translated into a closure the first time it is reached (or ahead of time if it is statically
+// reachable) and reused from then on. The closures run the Execute function of the registered instruction, so
+// registered opcodes are translated just like the built-in ones.
+//
+// Memory that is changed from outside, e.g. with IntCode.Set, is translated again before the program continues.
+// As soon as the program writes to an instruction that was already translated, or reaches something that cannot
+// be translated, the rest of the program is run by the interpreter. Tracing, history and Debug output are only
+// available from the interpreter, so machines with a Tracer, recorded history or Debug set are always interpreted.
+type CompiledProgram struct {
+	IntCode *IntCode
+
+	// Translated instruction for every address of the original program, nil where nothing was translated yet.
+	instructions []compiledInstruction
+	// Addresses that are part of translated instructions.
+	isCode []bool
+	// Set once the program has to be run by the interpreter.
+	interpreted bool
+	// Generation of the memory the instructions were translated from.
+	generation int
+}
+
+// Translates all statically reachable instructions of the machine's program.
+func Compile(intCode *IntCode) *CompiledProgram {
+	compiled := &CompiledProgram{IntCode: intCode}
+	compiled.compile()
+	return compiled
+}
+
+// Throws away all translated instructions and translates the statically reachable ones of the current memory.
+func (compiled *CompiledProgram) compile() {
+	code := compiled.IntCode.code.dense
+	compiled.instructions = make([]compiledInstruction, len(code))
+	compiled.isCode = make([]bool, len(code))
+	compiled.interpreted = false
+	compiled.generation = compiled.IntCode.code.generation
+
+	for address := range reachableInstructions(code) {
+		compiled.compileAt(address)
+	}
+}
+
+// Runs the program from the start until it halts, see IntCode.Run.
+func (compiled *CompiledProgram) Run() error {
+	compiled.IntCode.reset()
+
+	state, err := compiled.RunUntilBlocked()
+	if err == nil && state == WaitingForInput {
+		err = ErrNoInput
+	}
+	return err
+}
+
+// Like Run, but stops with a CancelledError once the context is done, see IntCode.RunContext.
+func (compiled *CompiledProgram) RunContext(ctx context.Context) error {
+	compiled.IntCode.ctx = ctx
+	defer func() { compiled.IntCode.ctx = nil }()
+	return compiled.Run()
+}
+
+// Runs the program from the start with the given inputs and returns everything that was output, see
+// IntCode.RunWithInput.
+func (compiled *CompiledProgram) RunWithInput(inputs ...int) ([]int, error) {
+	intCode := compiled.IntCode
+	intCode.Input = nil
+	intCode.Output = nil
+	intCode.inputQueue = inputs
+	intCode.outputQueue = nil
+
+	err := compiled.Run()
+	return intCode.TakeOutput(), err
+}
+
+// Continues running the program from the current instruction pointer until it halts, faults or waits for input,
+// see IntCode.RunUntilBlocked.
+func (compiled *CompiledProgram) RunUntilBlocked() (State, error) {
+	intCode := compiled.IntCode
+	if intCode.code.generation != compiled.generation {
+		compiled.compile()
+	}
+	intCode.beforeWrite = compiled.beforeWrite
+	defer func() { intCode.beforeWrite = nil }()
+	if intCode.Tracer != nil || intCode.history != nil || intCode.Debug {
+		compiled.interpreted = true
+	}
+
+	for !compiled.interpreted {
+		address := intCode.instructionPointer
+		if address < 0 || address >= len(compiled.instructions) {
+			break
+		}
+		if intCode.MaxSteps > 0 && intCode.steps >= intCode.MaxSteps {
+			// the interpreter reports the exceeded limit
+			break
+		}
+		if err := intCode.checkContext(); err != nil {
+			return Faulted, err
+		}
+
+		instruction := compiled.instructions[address]
+		if instruction == nil {
+			instruction = compiled.compileAt(address)
+			if instruction == nil {
+				break
+			}
+		}
+
+		state, err := instruction(intCode)
+		if err != nil {
+			state = Faulted
+		} else if state != WaitingForInput {
+			intCode.steps++
+		}
+		intCode.state = state
+		if state != Running {
+			return state, err
+		}
+	}
+
+	compiled.interpreted = true
+	return intCode.RunUntilBlocked()
+}
+
+// Translates the instruction at the given address of the current memory, returns nil if it cannot be translated.
+func (compiled *CompiledProgram) compileAt(address int) compiledInstruction {
+	code := compiled.IntCode.code.slice(address, maxInstructionLength())
+	info, modes, ok := decodeAt(code, 0)
+	if !ok {
+		return nil
+	}
+
+	if address+info.Parameters >= len(compiled.isCode) {
+		// writes behind the original program would not be noticed as changes to the instruction
+		return nil
+	}
+
+	opcode := code[0] % 100
+	operands := make([]compiledOperand, info.Parameters)
+	for i, mode := range modes {
+		value := code[1+i]
+		if mode == positionMode && value < 0 {
+			// leave reporting the invalid address to the interpreter
+			return nil
+		}
+		operands[i] = compileOperand(address, opcode, mode, value)
+	}
+
+	instruction := compiled.compileInstruction(address, info, operands)
+	compiled.instructions[address] = instruction
+	for i := address; i <= address+info.Parameters && i < len(compiled.isCode); i++ {
+		compiled.isCode[i] = true
+	}
+	return instruction
+}
+
+func compileOperand(address int, opcode int, mode int, value int) compiledOperand {
+	switch mode {
+	case immediateMode:
+		return func(intCode *IntCode) (int, int, error) {
+			return value, value, nil
+		}
+	case positionMode:
+		return func(intCode *IntCode) (int, int, error) {
+			return intCode.code.get(value), value, nil
+		}
+	}
+
+	return func(intCode *IntCode) (int, int, error) {
+		target := intCode.relativeBase + value
+		if target < 0 {
+			return 0, 0, &AddressOutOfBoundsError{address, opcode, target}
+		}
+		return intCode.code.get(target), target, nil
+	}
+}
+
+// Builds the closure executing an instruction with its parameters read by the operands, see IntCode.RunStep.
+// The parameter slices are reused by every execution of the instruction.
+func (compiled *CompiledProgram) compileInstruction(
+	address int,
+	info Instruction,
+	operands []compiledOperand,
+) compiledInstruction {
+	next := address + 1 + info.Parameters
+	params := make([]int, info.Parameters)
+	raw := make([]int, info.Parameters)
+
+	return func(intCode *IntCode) (State, error) {
+		for i, operand := range operands {
+			value, target, err := operand(intCode)
+			if err != nil {
+				return Faulted, err
+			}
+			params[i], raw[i] = value, target
+		}
+
+		intCode.jumped = false
+		state, err := info.Execute(intCode, params, raw)
+		compiled.generation = intCode.code.generation
+		if err != nil {
+			return Faulted, err
+		}
+		if state == Running && !intCode.jumped {
+			intCode.instructionPointer = next
+		}
+		return state, nil
+	}
+}
+
+// Switches to the interpreter if the program is about to change an already translated instruction.
+func (compiled *CompiledProgram) beforeWrite(address int, value int) {
+	if address >= 0 && address < len(compiled.isCode) && compiled.isCode[address] && compiled.IntCode.code.get(address) != value {
+		compiled.interpreted = true
+	}
+}
@@ -0,0 +1,143 @@
+package intcode
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// Sums up all numbers from the input down to 1, which keeps the machine busy in a tight loop.
+const benchmarkLoop = `
+        IN -> [n]
+loop:   ADD [sum], [n] -> [sum]
+        ADD [n], #-1 -> [n]
+        JT [n], loop
+        OUT [sum]
+        HALT
+n:      DATA 0
+sum:    DATA 0
+`
+
+func benchmarkProgram(b *testing.B, compile bool, program IntCode, input int, expected int) {
+	for i := 0; i < b.N; i++ {
+		intCode := program.Clone()
+
+		var outputs []int
+		var err error
+		if compile {
+			outputs, err = Compile(&intCode).RunWithInput(input)
+		} else {
+			outputs, err = intCode.RunWithInput(input)
+		}
+
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(outputs) == 0 || outputs[len(outputs)-1] != expected {
+			b.Fatalf("Expected %d but got %v", expected, outputs)
+		}
+	}
+}
+
+func loopProgram(b *testing.B) IntCode {
+	code, err := Assemble(benchmarkLoop)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return IntCode{code: newMemory(code)}
+}
+
+func day5Program(b *testing.B) IntCode {
+	input, err := ioutil.ReadFile("../day-5-part-2/input.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	intCode, err := NewIntCode(string(input))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return intCode
+}
+
+func TestCompiledProgramSeesChangesMadeWithSet(t *testing.T) {
+	program := "1,9,10,0,99,0,0,0,0,3,4"
+	interpreted, err := NewIntCode(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intCode, err := NewIntCode(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compiled := Compile(&intCode)
+	for _, machine := range []*IntCode{&interpreted, &intCode} {
+		machine.Set(1, 10)
+		machine.Set(2, 10)
+	}
+	if err := interpreted.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := compiled.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if intCode.Get(0) != 8 || intCode.String() != interpreted.String() {
+		t.Errorf("Expected %s but got %s", interpreted.String(), intCode.String())
+	}
+}
+
+func TestCompiledProgramRunsRegisteredInstructions(t *testing.T) {
+	double := Instruction{
+		Name:       "DBL",
+		Parameters: 2,
+		Writes:     []int{1},
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			intCode.Write(raw[1], params[0]*2)
+			return Running, nil
+		},
+	}
+	if err := Register(42, double); err != nil {
+		t.Fatal(err)
+	}
+	defer delete(instructionSet, 42)
+
+	code, err := Assemble(`
+        IN -> [n]
+loop:   DBL [value] -> [value]
+        ADD [n], #-1 -> [n]
+        JT [n], loop
+        OUT [value]
+        HALT
+n:      DATA 0
+value:  DATA 1
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intCode := IntCode{code: newMemory(code)}
+	compiled := Compile(&intCode)
+	outputs, err := compiled.RunWithInput(10)
+	if err != nil || !reflect.DeepEqual(outputs, []int{1024}) {
+		t.Errorf("Expected [1024] but got %v (%v)", outputs, err)
+	}
+	if compiled.interpreted {
+		t.Error("Expected the registered instruction to be translated instead of interpreted")
+	}
+}
+
+func BenchmarkInterpreterLoop(b *testing.B) {
+	benchmarkProgram(b, false, loopProgram(b), 10000, 50005000)
+}
+
+func BenchmarkCompiledLoop(b *testing.B) {
+	benchmarkProgram(b, true, loopProgram(b), 10000, 50005000)
+}
+
+func BenchmarkInterpreterDay5(b *testing.B) {
+	benchmarkProgram(b, false, day5Program(b), 5, 8805067)
+}
+
+func BenchmarkCompiledDay5(b *testing.B) {
+	benchmarkProgram(b, true, day5Program(b), 5, 8805067)
+}
@@ -0,0 +1,149 @@
+package intcode
+
+import "sort"
+
+// Why Debugger.Step or Debugger.Continue returned.
+type DebugStop struct {
+	// State of the machine after the last executed instruction.
+	State State
+	// Set if execution stopped in front of an instruction with a breakpoint on its address or opcode.
+	Breakpoint bool
+	// Watched addresses whose value changed during the last executed instruction.
+	Watchpoints []int
+}
+
+// Debugger runs a machine instruction by instruction and stops on breakpoints and watchpoints.
+type Debugger struct {
+	IntCode *IntCode
+
+	breakpoints       map[int]bool
+	opcodeBreakpoints map[int]bool
+	// Last known value of every watched address.
+	watchpoints map[int]int
+}
+
+// Creates a debugger for the machine and starts recording its history so it can be stepped backwards.
+func NewDebugger(intCode *IntCode) *Debugger {
+	intCode.RecordHistory()
+	return &Debugger{
+		IntCode:           intCode,
+		breakpoints:       make(map[int]bool),
+		opcodeBreakpoints: make(map[int]bool),
+		watchpoints:       make(map[int]int),
+	}
+}
+
+func (debugger *Debugger) AddBreakpoint(address int) {
+	debugger.breakpoints[address] = true
+}
+
+func (debugger *Debugger) RemoveBreakpoint(address int) {
+	delete(debugger.breakpoints, address)
+}
+
+// Breaks in front of every instruction with the given opcode.
+func (debugger *Debugger) AddOpcodeBreakpoint(opcode int) {
+	debugger.opcodeBreakpoints[opcode] = true
+}
+
+func (debugger *Debugger) RemoveOpcodeBreakpoint(opcode int) {
+	delete(debugger.opcodeBreakpoints, opcode)
+}
+
+// Stops execution after every instruction that changes the value at the given address.
+func (debugger *Debugger) AddWatchpoint(address int) {
+	debugger.watchpoints[address] = debugger.IntCode.Get(address)
+}
+
+func (debugger *Debugger) RemoveWatchpoint(address int) {
+	delete(debugger.watchpoints, address)
+}
+
+func (debugger *Debugger) Breakpoints() []int {
+	return sortedKeys(debugger.breakpoints)
+}
+
+func (debugger *Debugger) OpcodeBreakpoints() []int {
+	return sortedKeys(debugger.opcodeBreakpoints)
+}
+
+func (debugger *Debugger) Watchpoints() []int {
+	addresses := make([]int, 0, len(debugger.watchpoints))
+	for address := range debugger.watchpoints {
+		addresses = append(addresses, address)
+	}
+	sort.Ints(addresses)
+	return addresses
+}
+
+// Executes a single instruction, ignoring breakpoints. Machines that halted or faulted are left as they are.
+func (debugger *Debugger) Step() (DebugStop, error) {
+	if state := debugger.IntCode.state; state == Halted || state == Faulted {
+		return DebugStop{State: state, Watchpoints: []int{}}, nil
+	}
+
+	state, err := debugger.IntCode.RunStep()
+	return DebugStop{State: state, Watchpoints: debugger.changedWatchpoints()}, err
+}
+
+// Executes instructions until the machine stops running, a watched value changes or the next instruction
+// has a breakpoint. The current instruction is always executed, so continuing from a breakpoint moves past it.
+func (debugger *Debugger) Continue() (DebugStop, error) {
+	for {
+		stop, err := debugger.Step()
+		if err != nil || stop.State != Running || len(stop.Watchpoints) > 0 {
+			return stop, err
+		}
+		if debugger.isAtBreakpoint() {
+			stop.Breakpoint = true
+			return stop, nil
+		}
+	}
+}
+
+// Undoes the last executed instruction. Watchpoints report the addresses the undone instruction changed.
+func (debugger *Debugger) StepBack() (DebugStop, error) {
+	err := debugger.IntCode.StepBack()
+	return DebugStop{State: debugger.IntCode.state, Watchpoints: debugger.changedWatchpoints()}, err
+}
+
+// Steps back until the machine is in front of the instruction that last wrote to the address.
+func (debugger *Debugger) RunBackToWrite(address int) (TraceEvent, error) {
+	event, err := debugger.IntCode.RunBackToWrite(address)
+	debugger.changedWatchpoints()
+	return event, err
+}
+
+// Moves the machine backwards or forwards to the given step, ignoring breakpoints and watchpoints.
+func (debugger *Debugger) GoToStep(step int) (State, error) {
+	state, err := debugger.IntCode.GoToStep(step)
+	debugger.changedWatchpoints()
+	return state, err
+}
+
+func (debugger *Debugger) isAtBreakpoint() bool {
+	address := debugger.IntCode.instructionPointer
+	return debugger.breakpoints[address] || debugger.opcodeBreakpoints[debugger.IntCode.Get(address)%100]
+}
+
+// Returns the watched addresses that changed since the last call and remembers their new values.
+func (debugger *Debugger) changedWatchpoints() []int {
+	changed := make([]int, 0)
+	for _, address := range debugger.Watchpoints() {
+		value := debugger.IntCode.Get(address)
+		if value != debugger.watchpoints[address] {
+			changed = append(changed, address)
+			debugger.watchpoints[address] = value
+		}
+	}
+	return changed
+}
+
+func sortedKeys(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
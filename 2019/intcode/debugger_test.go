@@ -0,0 +1,103 @@
+package intcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Reads a value, increments it, outputs it and halts.
+const debuggerProgram = `
+        IN -> [x]
+        ADD [x], #1 -> [x]
+        OUT [x]
+        HALT
+x:      DATA 0
+`
+
+func newTestDebugger(t *testing.T) *Debugger {
+	code, err := Assemble(debuggerProgram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intCode := IntCode{code: newMemory(code)}
+	intCode.Feed(1)
+	return NewDebugger(&intCode)
+}
+
+func continueTo(t *testing.T, debugger *Debugger, expected DebugStop, address int) {
+	t.Helper()
+	stop, err := debugger.Continue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stop, expected) || debugger.IntCode.InstructionPointer() != address {
+		t.Errorf(
+			"Expected to stop with %+v at %d but got %+v at %d",
+			expected,
+			address,
+			stop,
+			debugger.IntCode.InstructionPointer(),
+		)
+	}
+}
+
+func TestDebuggerBreakpoints(t *testing.T) {
+	debugger := newTestDebugger(t)
+	debugger.AddBreakpoint(6)
+	continueTo(t, debugger, DebugStop{State: Running, Breakpoint: true, Watchpoints: []int{}}, 6)
+	continueTo(t, debugger, DebugStop{State: Halted, Watchpoints: []int{}}, 8)
+
+	debugger = newTestDebugger(t)
+	debugger.AddOpcodeBreakpoint(99)
+	continueTo(t, debugger, DebugStop{State: Running, Breakpoint: true, Watchpoints: []int{}}, 8)
+	if outputs := debugger.IntCode.TakeOutput(); !reflect.DeepEqual(outputs, []int{2}) {
+		t.Errorf("Expected [2] to be output before the breakpoint but got %v", outputs)
+	}
+}
+
+func TestDebuggerContinuesPastCurrentBreakpoint(t *testing.T) {
+	debugger := newTestDebugger(t)
+	debugger.AddBreakpoint(0)
+	debugger.AddOpcodeBreakpoint(3)
+	continueTo(t, debugger, DebugStop{State: Halted, Watchpoints: []int{}}, 8)
+}
+
+func TestDebuggerWatchpoints(t *testing.T) {
+	debugger := newTestDebugger(t)
+	debugger.AddWatchpoint(9)
+	continueTo(t, debugger, DebugStop{State: Running, Watchpoints: []int{9}}, 2)
+	continueTo(t, debugger, DebugStop{State: Running, Watchpoints: []int{9}}, 6)
+	continueTo(t, debugger, DebugStop{State: Halted, Watchpoints: []int{}}, 8)
+
+	stop, err := debugger.StepBack()
+	if err != nil || !reflect.DeepEqual(stop.Watchpoints, []int{}) {
+		t.Errorf("Expected undoing HALT to change nothing but got %+v (%v)", stop, err)
+	}
+}
+
+func TestDebuggerDoesNotStepStoppedMachines(t *testing.T) {
+	debugger := newTestDebugger(t)
+	continueTo(t, debugger, DebugStop{State: Halted, Watchpoints: []int{}}, 8)
+	steps := debugger.IntCode.Steps()
+	for i := 0; i < 3; i++ {
+		stop, err := debugger.Step()
+		if err != nil || stop.State != Halted {
+			t.Errorf("Expected the machine to stay halted but got %+v (%v)", stop, err)
+		}
+	}
+	if debugger.IntCode.Steps() != steps || len(debugger.IntCode.history) != steps {
+		t.Errorf("Expected %d steps but got %d with %d history entries", steps, debugger.IntCode.Steps(), len(debugger.IntCode.history))
+	}
+
+	intCode, err := NewIntCode("1,-1,0,0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	debugger = NewDebugger(&intCode)
+	if _, err := debugger.Step(); err == nil {
+		t.Fatal("Expected the first step to fault")
+	}
+	if stop, err := debugger.Step(); err != nil || stop.State != Faulted || intCode.Steps() != 0 {
+		t.Errorf("Expected the machine to stay faulted but got %+v (%v) after %d steps", stop, err, intCode.Steps())
+	}
+}
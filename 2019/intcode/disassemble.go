@@ -0,0 +1,240 @@
+package intcode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Number of values of unreachable memory that are grouped into a single line.
+const dataValuesPerLine = 8
+
+// A single line of a disassembled program: either one instruction or a run of raw data values.
+type DisassembledLine struct {
+	Address int
+	Values  []int
+	IsData  bool
+	// Set for instructions that cannot be reached from address 0 and were only found by decoding the values in
+	// order, they may as well be data. Their Text ends with an `; unreached` comment.
+	Unreached bool
+	Text      string
+}
+
+func (line DisassembledLine) String() string {
+	return fmt.Sprintf("%6d  %s", line.Address, line.Text)
+}
+
+// Turns a program into a readable listing. Instructions that can be reached from address 0 by stepping through
+// the program and following jumps to constant addresses are decoded first. The rest of the program, e.g. code
+// behind instructions that are only completed at runtime, is decoded in a linear sweep wherever the values form
+// a valid instruction, these are marked as unreached. All other values are listed as data.
+// Parameters are rendered depending on their mode: [9] is position mode, #9 immediate mode and [rb+9] relative mode.
+// Jump targets in immediate mode are rendered as plain addresses.
+func Disassemble(code []int) []DisassembledLine {
+	starts := reachableInstructions(code)
+	lines := make([]DisassembledLine, 0)
+
+	for address := 0; address < len(code); {
+		if info, modes, ok := instructionAt(code, starts, address); ok {
+			end := address + 1 + info.Parameters
+			if end > len(code) {
+				end = len(code)
+			}
+
+			line := DisassembledLine{
+				Address: address,
+				Values:  code[address:end],
+				Text:    formatInstruction(code, address, info, modes),
+			}
+			if !starts[address] {
+				line.Unreached = true
+				line.Text += "  ; unreached"
+			}
+			lines = append(lines, line)
+			address += 1 + info.Parameters
+			continue
+		}
+
+		start := address
+		address++
+		for address < len(code) && address-start < dataValuesPerLine {
+			if _, _, ok := instructionAt(code, starts, address); ok {
+				break
+			}
+			address++
+		}
+		lines = append(lines, DisassembledLine{
+			Address: start,
+			Values:  code[start:address],
+			IsData:  true,
+			Text:    "DATA " + joinValues(code[start:address]),
+		})
+	}
+
+	return lines
+}
+
+// Decodes the instruction at the given address for the listing: reachable instructions are always decoded, others
+// only if they fit into the program without overlapping a reachable instruction.
+func instructionAt(code []int, starts map[int]bool, address int) (info Instruction, modes []int, ok bool) {
+	info, modes, ok = decodeAt(code, address)
+	if !ok || starts[address] {
+		return
+	}
+
+	end := address + 1 + info.Parameters
+	if end > len(code) {
+		return info, modes, false
+	}
+	for i := address + 1; i < end; i++ {
+		if starts[i] {
+			return info, modes, false
+		}
+	}
+	return
+}
+
+// Disassembles the single instruction at the given address of the current memory of the machine. If there is no
+// valid instruction at that address the value is shown as data.
+func (intCode *IntCode) DisassembleAt(address int) DisassembledLine {
+	code := intCode.code.slice(address, maxInstructionLength())
+	info, modes, ok := decodeAt(code, 0)
+	if !ok {
+		return DisassembledLine{Address: address, Values: code[:1], IsData: true, Text: "DATA " + joinValues(code[:1])}
+	}
+
+	return DisassembledLine{
+		Address: address,
+		Values:  code[:1+info.Parameters],
+		Text:    formatInstruction(code, 0, info, modes),
+	}
+}
+
+// Decodes the instruction at the given address if it is one that the interpreter could execute.
+func decodeAt(code []int, address int) (info Instruction, modes []int, ok bool) {
+	if address < 0 || address >= len(code) {
+		return
+	}
+
+	info, known := instructionSet[code[address]%100]
+	if !known {
+		return
+	}
+
+	_, modes = decodeInstruction(code[address], info.Parameters)
+	for i, mode := range modes {
+		if mode != positionMode && mode != immediateMode && mode != relativeMode {
+			return
+		}
+		if mode == immediateMode && info.isWrite(i) {
+			return
+		}
+	}
+
+	return info, modes, true
+}
+
+// Finds the addresses of all instructions that can be reached from address 0.
+func reachableInstructions(code []int) map[int]bool {
+	starts := make(map[int]bool)
+	pending := []int{0}
+
+	for len(pending) > 0 {
+		address := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if starts[address] {
+			continue
+		}
+
+		info, modes, ok := decodeAt(code, address)
+		if !ok {
+			continue
+		}
+		starts[address] = true
+		pending = append(pending, nextInstructions(code, address, info, modes)...)
+	}
+
+	return starts
+}
+
+// Addresses that execution may continue at after the instruction at the given address. Jumps to addresses that
+// are only known at runtime cannot be followed.
+func nextInstructions(code []int, address int, info Instruction, modes []int) []int {
+	if info.Halts {
+		return nil
+	}
+
+	next := address + 1 + info.Parameters
+	if info.JumpTaken == nil {
+		return []int{next}
+	}
+
+	targets := make([]int, 0, 2)
+	if modes[info.JumpTarget] == immediateMode {
+		targets = append(targets, valueAt(code, address+1+info.JumpTarget))
+	}
+
+	// A jump with a constant condition is either always or never taken.
+	alwaysJumps := modes[0] == immediateMode && info.JumpTaken(valueAt(code, address+1))
+	if !alwaysJumps {
+		targets = append(targets, next)
+	}
+
+	return targets
+}
+
+func formatInstruction(code []int, address int, info Instruction, modes []int) string {
+	reads := make([]string, 0, info.Parameters)
+	writes := make([]string, 0, len(info.Writes))
+
+	for i, mode := range modes {
+		value := valueAt(code, address+1+i)
+		isJumpTarget := info.JumpTaken != nil && i == info.JumpTarget
+		if info.isWrite(i) {
+			writes = append(writes, formatParameter(value, mode, isJumpTarget))
+		} else {
+			reads = append(reads, formatParameter(value, mode, isJumpTarget))
+		}
+	}
+
+	text := info.Name
+	if len(reads) > 0 {
+		text += " " + strings.Join(reads, ", ")
+	}
+	if len(writes) > 0 {
+		text += " -> " + strings.Join(writes, ", ")
+	}
+	return text
+}
+
+func formatParameter(value int, mode int, isJumpTarget bool) string {
+	switch mode {
+	case immediateMode:
+		if isJumpTarget {
+			return strconv.Itoa(value)
+		}
+		return "#" + strconv.Itoa(value)
+	case relativeMode:
+		if value < 0 {
+			return fmt.Sprintf("[rb-%d]", -value)
+		}
+		return fmt.Sprintf("[rb+%d]", value)
+	}
+	return fmt.Sprintf("[%d]", value)
+}
+
+// Reads a value of the program, addresses outside of it read as 0 just like they do in the interpreter.
+func valueAt(code []int, address int) int {
+	if address < 0 || address >= len(code) {
+		return 0
+	}
+	return code[address]
+}
+
+func joinValues(values []int) string {
+	str := make([]string, len(values))
+	for i, value := range values {
+		str[i] = strconv.Itoa(value)
+	}
+	return strings.Join(str, ", ")
+}
@@ -0,0 +1,61 @@
+package intcode
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// Every program has to behave the same when it is disassembled and assembled again.
+func TestAssembleDisassembleRoundTrip(t *testing.T) {
+	for _, example := range day5Examples {
+		code := parseTestProgram(t, example.program)
+		lines := Disassemble(code)
+		source := ""
+		for _, line := range lines {
+			source += line.Text + "\n"
+		}
+
+		assembled, err := Assemble(source)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", example.program, err, source)
+		}
+		if !reflect.DeepEqual(assembled, code) {
+			t.Errorf("%s: assembled to %v", example.program, assembled)
+		}
+	}
+}
+
+func TestDisassembleCodePatchedAtRuntime(t *testing.T) {
+	input, err := ioutil.ReadFile("../day-5-part-2/input.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := ParseProgram(string(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the instruction at 6 is only completed by the input, everything behind it is decoded but marked as unreached
+	lines := Disassemble(code)
+	expected := []DisassembledLine{
+		{Address: 0, Values: []int{3, 225}, Text: "IN -> [225]"},
+		{Address: 2, Values: []int{1, 225, 6, 6}, Text: "ADD [225], [6] -> [6]"},
+		{Address: 6, Values: []int{1100}, IsData: true, Text: "DATA 1100"},
+		{Address: 7, Values: []int{1, 238, 225, 104}, Unreached: true, Text: "ADD [238], [225] -> [104]  ; unreached"},
+		{Address: 11, Values: []int{0}, IsData: true, Text: "DATA 0"},
+		{Address: 12, Values: []int{1101, 82, 10, 225}, Unreached: true, Text: "ADD #82, #10 -> [225]  ; unreached"},
+	}
+	if len(lines) < len(expected) || !reflect.DeepEqual(lines[:len(expected)], expected) {
+		t.Errorf("Expected the listing to start with %v but got %v", expected, lines)
+	}
+
+	source := ""
+	for _, line := range lines {
+		source += line.Text + "\n"
+	}
+	assembled, err := Assemble(source)
+	if err != nil || !reflect.DeepEqual(assembled, code) {
+		t.Errorf("Expected the listing to assemble to the original program (%v)", err)
+	}
+}
@@ -0,0 +1,133 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// Returned if the program needs input but neither fed values nor an Input channel are available.
+	ErrNoInput = errors.New("Input instruction encountered but no input is available")
+	// Returned if the program needs input but the Input channel has been closed.
+	ErrInputClosed = errors.New("Input instruction encountered but the input has been closed")
+)
+
+// Returned by NewIntCode and ParseProgram if the program contains something that is not a number.
+type ParseError struct {
+	Address int
+	Value   string
+	Err     error
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("Invalid value %q at address %d: %v", err.Value, err.Address, err.Err)
+}
+
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
+type InvalidOpcodeError struct {
+	InstructionPointer int
+	Opcode             int
+}
+
+func (err *InvalidOpcodeError) Error() string {
+	return fmt.Sprintf("Invalid intcode instruction %d encountered at position %d", err.Opcode, err.InstructionPointer)
+}
+
+type InvalidParameterModeError struct {
+	InstructionPointer int
+	Opcode             int
+	Parameter          int
+	Mode               int
+}
+
+func (err *InvalidParameterModeError) Error() string {
+	return fmt.Sprintf(
+		"Unknown parameter mode %d for parameter %d of intcode %d at position %d",
+		err.Mode,
+		err.Parameter,
+		err.Opcode,
+		err.InstructionPointer,
+	)
+}
+
+// Returned if an instruction (or the instruction pointer itself) refers to a negative address.
+type AddressOutOfBoundsError struct {
+	InstructionPointer int
+	Opcode             int
+	Address            int
+}
+
+func (err *AddressOutOfBoundsError) Error() string {
+	return fmt.Sprintf(
+		"Address %d accessed by intcode %d at position %d is out of bounds",
+		err.Address,
+		err.Opcode,
+		err.InstructionPointer,
+	)
+}
+
+// Returned if a parameter that an instruction writes to is in immediate mode.
+type ImmediateWriteError struct {
+	InstructionPointer int
+	Opcode             int
+	Parameter          int
+}
+
+func (err *ImmediateWriteError) Error() string {
+	return fmt.Sprintf(
+		"Parameter %d of intcode %d at position %d is written to but in immediate mode",
+		err.Parameter,
+		err.Opcode,
+		err.InstructionPointer,
+	)
+}
+
+// Returned if a run executes more instructions than IntCode.MaxSteps allows.
+type StepLimitExceededError struct {
+	InstructionPointer int
+	Opcode             int
+	Steps              int
+}
+
+func (err *StepLimitExceededError) Error() string {
+	return fmt.Sprintf(
+		"Step limit of %d exceeded before intcode %d at position %d",
+		err.Steps,
+		err.Opcode,
+		err.InstructionPointer,
+	)
+}
+
+// Returned by RunContext once its context is done, Err is the error of the context.
+type CancelledError struct {
+	InstructionPointer int
+	Steps              int
+	Err                error
+}
+
+func (err *CancelledError) Error() string {
+	return fmt.Sprintf("Run cancelled at position %d after %d steps: %v", err.InstructionPointer, err.Steps, err.Err)
+}
+
+func (err *CancelledError) Unwrap() error {
+	return err.Err
+}
+
+// Returned if CheckOverflow is set and the result of an instruction does not fit into an int.
+type OverflowError struct {
+	InstructionPointer int
+	Opcode             int
+	Operands           []int
+}
+
+func (err *OverflowError) Error() string {
+	return fmt.Sprintf(
+		"Integer overflow in intcode %d at position %d with operands %v",
+		err.Opcode,
+		err.InstructionPointer,
+		err.Operands,
+	)
+}
@@ -0,0 +1,85 @@
+package intcode
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// Keeps fuzzed programs that loop forever short.
+const fuzzMaxSteps = 10000
+
+func addFuzzPrograms(f *testing.F, add func(program string)) {
+	for _, example := range day2Examples {
+		add(example.program)
+	}
+	for _, example := range day5Examples {
+		add(example.program)
+	}
+	for _, file := range []string{"../day-2-part-1/input.txt", "../day-5-part-2/input.txt"} {
+		input, err := ioutil.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		add(string(input))
+	}
+}
+
+// Parsing arbitrary text must either fail or produce a program that formats back to the parsed values.
+func FuzzNewIntCode(f *testing.F) {
+	addFuzzPrograms(f, func(program string) { f.Add(program) })
+	f.Add("")
+	f.Add("1,,2")
+	f.Add(" 1, 2 ,3\n")
+	f.Add("99999999999999999999999")
+
+	f.Fuzz(func(t *testing.T, program string) {
+		intCode, err := NewIntCode(program)
+		if err != nil {
+			return
+		}
+
+		reparsed, err := NewIntCode(intCode.String())
+		if err != nil {
+			t.Fatalf("Formatted program %q does not parse: %v", intCode.String(), err)
+		}
+		if reparsed.String() != intCode.String() {
+			t.Errorf("Expected %s after formatting and parsing but got %s", intCode.String(), reparsed.String())
+		}
+	})
+}
+
+// Running arbitrary programs must never panic, the interpreter and the compiled program have to agree on the
+// outcome and the static tools have to cope with whatever the program contains.
+func FuzzRun(f *testing.F) {
+	addFuzzPrograms(f, func(program string) { f.Add(program, 5) })
+	// modes in digits that do not fit into a float64
+	f.Add("900719925474100101,5,0,0,4,0,99", 0)
+
+	f.Fuzz(func(t *testing.T, program string, input int) {
+		code, err := ParseProgram(program)
+		if err != nil {
+			return
+		}
+		Disassemble(code)
+		Vet(code)
+
+		interpreted := newTestIntCode(t, program)
+		interpreted.MaxSteps = fuzzMaxSteps
+		interpretedOutputs, interpretedErr := interpreted.RunWithInput(input, input)
+
+		compiled := newTestIntCode(t, program)
+		compiled.MaxSteps = fuzzMaxSteps
+		compiledOutputs, compiledErr := Compile(&compiled).RunWithInput(input, input)
+
+		if !reflect.DeepEqual(interpretedOutputs, compiledOutputs) {
+			t.Errorf("Interpreter output %v but compiled program output %v", interpretedOutputs, compiledOutputs)
+		}
+		if !reflect.DeepEqual(interpretedErr, compiledErr) {
+			t.Errorf("Interpreter failed with %v but compiled program with %v", interpretedErr, compiledErr)
+		}
+		if interpreted.String() != compiled.String() {
+			t.Errorf("Interpreter and compiled program left different memory behind")
+		}
+	})
+}
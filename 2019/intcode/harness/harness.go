@@ -0,0 +1,193 @@
+// Package harness runs several copies of an intcode program at the same time and wires their inputs and
+// outputs together, e.g. as a chain of amplifiers or as a network of computers sending packets to each other.
+package harness
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+var (
+	// Returned if every machine that has not halted is waiting for input and nothing can be delivered to them.
+	ErrDeadlock = errors.New("All machines are waiting for input")
+	// Returned if a packet network stopped sending packets and there is nothing that could wake it up again.
+	ErrIdle = errors.New("The network is idle")
+)
+
+// Decides where the outputs of the machines in a network go.
+type Topology interface {
+	// Called with everything a machine output since it was last started. Returns the values to feed to
+	// each machine by its index in the network, values for indexes without a machine are collected in
+	// Result.Undelivered.
+	Route(network *Network, from int, outputs []int) (map[int][]int, error)
+	// Called when no machine is running and nothing is left to deliver, with the indexes of the machines
+	// that are waiting for input. Returns the values to feed to wake the network up again, or nothing to stop
+	// the network with the returned error.
+	Blocked(network *Network, waiting []int) (map[int][]int, error)
+}
+
+// What the machines of a network did after it stopped.
+type Result struct {
+	// State of every machine.
+	States []intcode.State
+	// Every value each machine output, in order.
+	Outputs [][]int
+	// Values that were routed to indexes without a machine, e.g. the output of the last amplifier in a series.
+	Undelivered map[int][]int
+}
+
+// Network of machines that run concurrently, each in its own goroutine.
+type Network struct {
+	// Machines by their index. Values fed to them before Run are the first inputs they read, their Input and
+	// Output channels are not used.
+	Machines []*intcode.IntCode
+	Topology Topology
+}
+
+// Creates a network of count independent copies of the program.
+func NewNetwork(program intcode.IntCode, count int, topology Topology) *Network {
+	machines := make([]*intcode.IntCode, count)
+	for i := range machines {
+		machine := program.Clone()
+		machines[i] = &machine
+	}
+	return &Network{Machines: machines, Topology: topology}
+}
+
+// Outcome of running a machine until it blocked.
+type report struct {
+	machine int
+	state   intcode.State
+	err     error
+	outputs []int
+}
+
+// Starts all machines and passes values between them until every machine halted, the network deadlocked or
+// the topology stopped it. A network without machines is done right away.
+func (network *Network) Run() (Result, error) {
+	count := len(network.Machines)
+	result := Result{
+		States:      make([]intcode.State, count),
+		Outputs:     make([][]int, count),
+		Undelivered: make(map[int][]int),
+	}
+	if count == 0 {
+		return result, nil
+	}
+
+	reports := make(chan report)
+	wake := make([]chan []int, count)
+	for i, machine := range network.Machines {
+		wake[i] = make(chan []int, 1)
+		machine.Input = nil
+		machine.Output = nil
+		go runMachine(i, machine, wake[i], reports)
+	}
+	defer func() {
+		for _, channel := range wake {
+			close(channel)
+		}
+	}()
+
+	pending := make(map[int][]int)
+	running := make([]bool, count)
+	runningCount := 0
+	start := func(machine int) {
+		wake[machine] <- pending[machine]
+		delete(pending, machine)
+		running[machine] = true
+		runningCount++
+	}
+	deliver := func(deliveries map[int][]int) {
+		for machine, values := range deliveries {
+			if machine < 0 || machine >= count {
+				result.Undelivered[machine] = append(result.Undelivered[machine], values...)
+				continue
+			}
+			pending[machine] = append(pending[machine], values...)
+		}
+	}
+
+	for i := range network.Machines {
+		start(i)
+	}
+
+	for {
+		report := <-reports
+		running[report.machine] = false
+		runningCount--
+		result.States[report.machine] = report.state
+		result.Outputs[report.machine] = append(result.Outputs[report.machine], report.outputs...)
+		if report.err != nil {
+			return network.stop(result, running, reports, fmt.Errorf("Machine %d: %w", report.machine, report.err))
+		}
+
+		if len(report.outputs) > 0 {
+			deliveries, err := network.Topology.Route(network, report.machine, report.outputs)
+			if err != nil {
+				return network.stop(result, running, reports, err)
+			}
+			deliver(deliveries)
+		}
+
+		if runningCount == 0 && !hasPending(pending, result.States) {
+			waiting := make([]int, 0)
+			for i, state := range result.States {
+				if state == intcode.WaitingForInput {
+					waiting = append(waiting, i)
+				}
+			}
+			if len(waiting) == 0 {
+				return result, nil
+			}
+
+			deliveries, err := network.Topology.Blocked(network, waiting)
+			if len(deliveries) == 0 {
+				return result, err
+			}
+			deliver(deliveries)
+			if !hasPending(pending, result.States) {
+				return result, ErrDeadlock
+			}
+		}
+
+		for i, state := range result.States {
+			if !running[i] && state == intcode.WaitingForInput && len(pending[i]) > 0 {
+				start(i)
+			}
+		}
+	}
+}
+
+// Waits for the machines that are still running before returning the error.
+func (network *Network) stop(result Result, running []bool, reports <-chan report, err error) (Result, error) {
+	for _, isRunning := range running {
+		if isRunning {
+			report := <-reports
+			result.States[report.machine] = report.state
+			result.Outputs[report.machine] = append(result.Outputs[report.machine], report.outputs...)
+		}
+	}
+	return result, err
+}
+
+// Whether any machine that waits for input has values to read.
+func hasPending(pending map[int][]int, states []intcode.State) bool {
+	for machine, values := range pending {
+		if len(values) > 0 && states[machine] == intcode.WaitingForInput {
+			return true
+		}
+	}
+	return false
+}
+
+// Runs the machine whenever it receives inputs until the wake channel is closed.
+func runMachine(index int, machine *intcode.IntCode, wake <-chan []int, reports chan<- report) {
+	for inputs := range wake {
+		machine.Feed(inputs...)
+		state, err := machine.RunUntilBlocked()
+		reports <- report{index, state, err, machine.TakeOutput()}
+	}
+}
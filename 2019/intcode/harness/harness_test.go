@@ -0,0 +1,117 @@
+package harness
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+// Machine 0 sends the packet 5,6 to address 255, every machine then keeps reading input forever.
+const packetProgram = `
+        IN -> [address]
+        JT [address], listen
+        OUT #255
+        OUT #5
+        OUT #6
+listen: IN -> [value]
+        JT #1, listen
+address: DATA 0
+value:   DATA 0
+`
+
+func amplifiers(t *testing.T, program string, phases []int, topology Topology) *Network {
+	intCode, err := intcode.NewIntCode(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network := NewNetwork(intCode, len(phases), topology)
+	for i, phase := range phases {
+		network.Machines[i].Feed(phase)
+	}
+	if len(phases) > 0 {
+		network.Machines[0].Feed(0)
+	}
+	return network
+}
+
+func packetNetwork(t *testing.T, nat bool) *Network {
+	code, err := intcode.Assemble(packetProgram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intCode, err := intcode.NewIntCode(intcode.FormatProgram(code))
+	if err != nil {
+		t.Fatal(err)
+	}
+	network := NewNetwork(intCode, 3, NewPacketNetwork(nat))
+	for i, machine := range network.Machines {
+		machine.Feed(i)
+	}
+	return network
+}
+
+func TestSeries(t *testing.T) {
+	network := amplifiers(t, "3,15,3,16,1002,16,10,16,1,16,15,15,4,15,99,0,0", []int{4, 3, 2, 1, 0}, Series{})
+	result, err := network.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Undelivered[5], []int{43210}) {
+		t.Errorf("Expected 43210 but got %v", result.Undelivered)
+	}
+}
+
+func TestFeedbackLoop(t *testing.T) {
+	network := amplifiers(
+		t,
+		"3,26,1001,26,-4,26,3,27,1002,27,2,27,1,27,26,27,4,27,1001,28,-1,28,1005,28,6,99,0,0,5",
+		[]int{9, 8, 7, 6, 5},
+		FeedbackLoop{},
+	)
+	result, err := network.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := result.Outputs[4]
+	if len(outputs) == 0 || outputs[len(outputs)-1] != 139629729 {
+		t.Errorf("Expected 139629729 but got %v", outputs)
+	}
+}
+
+func TestDeadlock(t *testing.T) {
+	network := amplifiers(t, "3,0,3,0,3,0,99", []int{1, 2}, FeedbackLoop{})
+	if _, err := network.Run(); !errors.Is(err, ErrDeadlock) {
+		t.Errorf("Expected ErrDeadlock but got %v", err)
+	}
+}
+
+func TestEmptyNetwork(t *testing.T) {
+	network := amplifiers(t, "99", nil, Series{})
+	result, err := network.Run()
+	if err != nil || len(result.States) != 0 {
+		t.Errorf("Expected an empty result but got %v (%v)", result, err)
+	}
+}
+
+func TestPacketNetworkIdle(t *testing.T) {
+	result, err := packetNetwork(t, false).Run()
+	if !errors.Is(err, ErrIdle) {
+		t.Errorf("Expected ErrIdle but got %v", err)
+	}
+	if !reflect.DeepEqual(result.Undelivered[255], []int{5, 6}) {
+		t.Errorf("Expected the packet 5,6 for address 255 but got %v", result.Undelivered)
+	}
+}
+
+func TestPacketNetworkNAT(t *testing.T) {
+	network := packetNetwork(t, true)
+	if _, err := network.Run(); err != nil {
+		t.Fatal(err)
+	}
+	deliveries := network.Topology.(*PacketNetwork).NATDeliveries
+	if !reflect.DeepEqual(deliveries, [][]int{{5, 6}, {5, 6}}) {
+		t.Errorf("Expected the NAT to deliver 5,6 twice but got %v", deliveries)
+	}
+}
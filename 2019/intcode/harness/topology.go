@@ -0,0 +1,104 @@
+package harness
+
+import "github.com/j6s/adventofcode/2019/intcode"
+
+// Number of times in a row every machine of a packet network has to receive -1 without sending a packet
+// before the network counts as idle.
+const idleThreshold = 2
+
+// Machines passing their outputs to the next machine, the outputs of the last machine end up in
+// Result.Undelivered at the index after it.
+type Series struct{}
+
+func (series Series) Route(network *Network, from int, outputs []int) (map[int][]int, error) {
+	return map[int][]int{from + 1: outputs}, nil
+}
+
+func (series Series) Blocked(network *Network, waiting []int) (map[int][]int, error) {
+	return nil, ErrDeadlock
+}
+
+// Machines passing their outputs to the next machine, with the last one passing its outputs back to the first.
+// The last output of the last machine is in Result.Outputs.
+type FeedbackLoop struct{}
+
+func (loop FeedbackLoop) Route(network *Network, from int, outputs []int) (map[int][]int, error) {
+	return map[int][]int{(from + 1) % len(network.Machines): outputs}, nil
+}
+
+func (loop FeedbackLoop) Blocked(network *Network, waiting []int) (map[int][]int, error) {
+	return nil, ErrDeadlock
+}
+
+// Machines sending packets of three values to each other: the address of the receiving machine followed by
+// an X and a Y value. Machines that wait for input while no packets are queued for them receive -1.
+//
+// The network is idle once all machines kept receiving -1 without sending anything. Without a NAT that stops
+// the network with ErrIdle. With a NAT the last packet sent to NATAddress is delivered to machine 0 instead,
+// and the network stops as soon as the NAT delivers the same Y value twice in a row.
+type PacketNetwork struct {
+	NAT        bool
+	NATAddress int
+
+	// Outputs of every machine that do not make up a full packet yet.
+	partial map[int][]int
+	// Number of times in a row the network was blocked without a packet being sent in between.
+	idleRounds int
+	natPacket  []int
+	// Packets the NAT delivered to machine 0.
+	NATDeliveries [][]int
+}
+
+// Creates a packet network for the Category Six puzzle, where the NAT listens on address 255.
+func NewPacketNetwork(nat bool) *PacketNetwork {
+	return &PacketNetwork{NAT: nat, NATAddress: 255}
+}
+
+func (packets *PacketNetwork) Route(network *Network, from int, outputs []int) (map[int][]int, error) {
+	if packets.partial == nil {
+		packets.partial = make(map[int][]int)
+	}
+
+	buffer := append(packets.partial[from], outputs...)
+	deliveries := make(map[int][]int)
+	for len(buffer) >= 3 {
+		address := buffer[0]
+		if packets.NAT && address == packets.NATAddress {
+			packets.natPacket = []int{buffer[1], buffer[2]}
+		}
+		deliveries[address] = append(deliveries[address], buffer[1], buffer[2])
+		buffer = buffer[3:]
+		packets.idleRounds = 0
+	}
+	packets.partial[from] = buffer
+
+	if packets.NAT {
+		// the NAT keeps the packets and only delivers the last one once the network is idle
+		delete(deliveries, packets.NATAddress)
+	}
+	return deliveries, nil
+}
+
+func (packets *PacketNetwork) Blocked(network *Network, waiting []int) (map[int][]int, error) {
+	packets.idleRounds++
+	if packets.idleRounds < idleThreshold {
+		deliveries := make(map[int][]int)
+		for _, machine := range waiting {
+			deliveries[machine] = []int{-1}
+		}
+		return deliveries, nil
+	}
+
+	if !packets.NAT || packets.natPacket == nil || network.Machines[0].State() != intcode.WaitingForInput {
+		return nil, ErrIdle
+	}
+
+	last := len(packets.NATDeliveries) - 1
+	packets.NATDeliveries = append(packets.NATDeliveries, packets.natPacket)
+	if last >= 0 && packets.NATDeliveries[last][1] == packets.natPacket[1] {
+		return nil, nil
+	}
+
+	packets.idleRounds = 0
+	return map[int][]int{0: packets.natPacket}, nil
+}
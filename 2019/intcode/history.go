@@ -0,0 +1,97 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Returned when stepping back further than the recorded history goes.
+var ErrNoHistory = errors.New("No recorded history to step back to")
+
+// Everything needed to undo an executed instruction.
+type historyEntry struct {
+	event        TraceEvent
+	relativeBase int
+	state        State
+}
+
+// Starts recording an undo log of every executed instruction, which makes it possible to step backwards with
+// StepBack, RunBackToWrite and GoToStep. Only instructions executed from now on can be undone.
+func (intCode *IntCode) RecordHistory() {
+	if intCode.history == nil {
+		intCode.history = make([]historyEntry, 0)
+	}
+}
+
+// Number of instructions executed since the program was started.
+func (intCode *IntCode) Steps() int {
+	return intCode.steps
+}
+
+// Undoes the last executed instruction: memory writes are reverted, inputs it read are fed again and outputs it
+// produced are removed if they were not sent to an Output channel.
+func (intCode *IntCode) StepBack() error {
+	if len(intCode.history) == 0 {
+		return ErrNoHistory
+	}
+
+	entry := intCode.history[len(intCode.history)-1]
+	intCode.history = intCode.history[:len(intCode.history)-1]
+
+	for i := len(entry.event.Writes) - 1; i >= 0; i-- {
+		write := entry.event.Writes[i]
+		intCode.code.set(write.Address, write.OldValue)
+	}
+	if len(entry.event.Inputs) > 0 {
+		intCode.inputQueue = append(append([]int{}, entry.event.Inputs...), intCode.inputQueue...)
+	}
+	if intCode.Output == nil && len(intCode.outputQueue) >= len(entry.event.Outputs) {
+		intCode.outputQueue = intCode.outputQueue[:len(intCode.outputQueue)-len(entry.event.Outputs)]
+	}
+
+	intCode.instructionPointer = entry.event.InstructionPointer
+	intCode.relativeBase = entry.relativeBase
+	intCode.state = entry.state
+	intCode.steps = entry.event.Step
+	return nil
+}
+
+// Steps back until the instruction that last wrote to the address is undone, so the machine is right in front of
+// it, and returns that instruction.
+func (intCode *IntCode) RunBackToWrite(address int) (TraceEvent, error) {
+	for i := len(intCode.history) - 1; i >= 0; i-- {
+		for _, write := range intCode.history[i].event.Writes {
+			if write.Address != address {
+				continue
+			}
+
+			event := intCode.history[i].event
+			for len(intCode.history) > i {
+				intCode.StepBack()
+			}
+			return event, nil
+		}
+	}
+
+	return TraceEvent{}, fmt.Errorf("No recorded instruction wrote to address %d", address)
+}
+
+// Moves the machine to the point right before the given step was executed: backwards through the history or
+// forwards by running the program. Going forwards stops early if the machine stops running.
+func (intCode *IntCode) GoToStep(step int) (State, error) {
+	for intCode.steps > step {
+		err := intCode.StepBack()
+		if err != nil {
+			return intCode.state, err
+		}
+	}
+
+	for intCode.steps < step {
+		state, err := intCode.RunStep()
+		if state != Running {
+			return state, err
+		}
+	}
+
+	return intCode.state, nil
+}
@@ -0,0 +1,29 @@
+package intcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Stepping back through the whole program has to restore the state from before it started.
+func TestStepBackRestoresProgram(t *testing.T) {
+	for _, example := range day5Examples {
+		intCode := newTestIntCode(t, example.program)
+		intCode.RecordHistory()
+		intCode.Feed(example.input)
+		before := intCode.String()
+
+		if _, err := intCode.RunUntilBlocked(); err != nil {
+			t.Fatalf("%s: %v", example.program, err)
+		}
+		for intCode.StepBack() == nil {
+		}
+
+		if intCode.String() != before || intCode.InstructionPointer() != 0 || intCode.Steps() != 0 {
+			t.Errorf("%s: expected %s at 0 but got %s at %d", example.program, before, intCode.String(), intCode.InstructionPointer())
+		}
+		if outputs, err := intCode.RunWithInput(example.input); err != nil || !reflect.DeepEqual(outputs, example.expected) {
+			t.Errorf("%s: expected %v after stepping back but got %v (%v)", example.program, example.expected, outputs, err)
+		}
+	}
+}
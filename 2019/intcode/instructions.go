@@ -0,0 +1,293 @@
+package intcode
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	positionMode  = 0
+	immediateMode = 1
+	relativeMode  = 2
+)
+
+// Executes an instruction. parameters contain the values of all parameters with their mode applied, addresses
+// the addresses that the parameters refer to (including the relative base), which are used for the parameters
+// that are written to.
+//
+// The instruction pointer moves past the instruction afterwards, unless the instruction called IntCode.Jump or
+// returned a state other than Running. Instructions must change memory with IntCode.Write only, and must not
+// keep the slices after they returned.
+type ExecuteFunc func(intCode *IntCode, parameters []int, addresses []int) (State, error)
+
+// Description of an opcode that is used by the interpreter as well as the tools working on programs, such as
+// the disassembler, assembler and debugger.
+type Instruction struct {
+	// Mnemonic used by the disassembler and assembler.
+	Name       string
+	Parameters int
+	// Indexes of the parameters that are written to, these must not be in immediate mode.
+	Writes  []int
+	Execute ExecuteFunc
+
+	// Set for jumps: decides by the value of the first parameter whether the jump to the address in the
+	// parameter with the index JumpTarget is taken. Only used to follow the control flow statically.
+	JumpTaken  func(condition int) bool
+	JumpTarget int
+	// Whether execution stops after the instruction.
+	Halts bool
+}
+
+var instructionSet = map[int]Instruction{
+	// Opcode 1 adds together numbers read from two positions and stores the result in a third position.
+	// The three integers immediately after the opcode tell you these three positions - the first two indicate
+	// the positions from which you should read the input values, and the third indicates the position at which
+	// the output should be stored.
+	1: {
+		Name:       "ADD",
+		Parameters: 3,
+		Writes:     []int{2},
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			sum, err := intCode.add(params[0], params[1])
+			if err != nil {
+				return Faulted, err
+			}
+			intCode.Write(raw[2], sum)
+			return Running, nil
+		},
+	},
+	// Opcode 2 works exactly like opcode 1, except it multiplies the two inputs instead of adding them.
+	// Again, the three integers after the opcode indicate where the inputs and outputs are, not their values.
+	2: {
+		Name:       "MUL",
+		Parameters: 3,
+		Writes:     []int{2},
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			product, err := intCode.multiply(params[0], params[1])
+			if err != nil {
+				return Faulted, err
+			}
+			intCode.Write(raw[2], product)
+			return Running, nil
+		},
+	},
+	// Opcode 3 takes a single integer as input and saves it to the position given by its only parameter.
+	// For example, the instruction 3,50 would take an input value and store it at address 50.
+	// If no input is available the instruction pointer stays in place so the instruction is retried on resume.
+	3: {
+		Name:       "IN",
+		Parameters: 1,
+		Writes:     []int{0},
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			value, ok, err := intCode.ReadInput()
+			if err != nil {
+				return Faulted, err
+			}
+			if !ok {
+				return WaitingForInput, nil
+			}
+			intCode.Write(raw[0], value)
+			return Running, nil
+		},
+	},
+	// Opcode 4 outputs the value of its only parameter. For example, the instruction 4,50 would output the value at address 50.
+	4: {
+		Name:       "OUT",
+		Parameters: 1,
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			if err := intCode.WriteOutput(params[0]); err != nil {
+				return Faulted, err
+			}
+			return Running, nil
+		},
+	},
+	// Opcode 5 is jump-if-true: if the first parameter is non-zero, it sets the instruction pointer to the
+	// value from the second parameter. Otherwise, it does nothing.
+	5: {
+		Name:       "JT",
+		Parameters: 2,
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			if params[0] != 0 {
+				intCode.Jump(params[1])
+			}
+			return Running, nil
+		},
+		JumpTaken:  func(condition int) bool { return condition != 0 },
+		JumpTarget: 1,
+	},
+	// Opcode 6 is jump-if-false: if the first parameter is zero, it sets the instruction pointer to the value
+	// from the second parameter. Otherwise, it does nothing.
+	6: {
+		Name:       "JF",
+		Parameters: 2,
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			if params[0] == 0 {
+				intCode.Jump(params[1])
+			}
+			return Running, nil
+		},
+		JumpTaken:  func(condition int) bool { return condition == 0 },
+		JumpTarget: 1,
+	},
+	// Opcode 7 is less than: if the first parameter is less than the second parameter, it stores 1 in the position
+	// given by the third parameter. Otherwise, it stores 0.
+	7: {
+		Name:       "LT",
+		Parameters: 3,
+		Writes:     []int{2},
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			intCode.Write(raw[2], boolToInt(params[0] < params[1]))
+			return Running, nil
+		},
+	},
+	// Opcode 8 is equals: if the first parameter is equal to the second parameter, it stores 1 in the position
+	// given by the third parameter. Otherwise, it stores 0.
+	8: {
+		Name:       "EQ",
+		Parameters: 3,
+		Writes:     []int{2},
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			intCode.Write(raw[2], boolToInt(params[0] == params[1]))
+			return Running, nil
+		},
+	},
+	// Opcode 9 adjusts the relative base by the value of its only parameter. The relative base increases
+	// (or decreases, if the value is negative) by the value of the parameter.
+	9: {
+		Name:       "ARB",
+		Parameters: 1,
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			intCode.relativeBase += params[0]
+			return Running, nil
+		},
+	},
+	// Opcode 99 terminates the program
+	99: {
+		Name:       "HALT",
+		Parameters: 0,
+		Execute: func(intCode *IntCode, params []int, raw []int) (State, error) {
+			return Halted, nil
+		},
+		Halts: true,
+	},
+}
+
+// Adds an opcode to the instruction set. Opcodes are global for all machines, so instructions should be
+// registered before any machine runs, e.g. in an init function of the day using them.
+func Register(opcode int, instruction Instruction) error {
+	if opcode <= 0 || opcode > 99 {
+		return fmt.Errorf("Opcode %d is out of range, opcodes must be between 1 and 99", opcode)
+	}
+	if existing, known := instructionSet[opcode]; known {
+		return fmt.Errorf("Opcode %d is already registered as %s", opcode, existing.Name)
+	}
+	if instruction.Name == "" || strings.EqualFold(instruction.Name, "DATA") || strings.ContainsAny(instruction.Name, " \t,:;[]#-") {
+		return fmt.Errorf("Invalid name %q for opcode %d", instruction.Name, opcode)
+	}
+	if existing, known := LookupMnemonic(instruction.Name); known {
+		return fmt.Errorf("Name %s is already used by opcode %d", instruction.Name, existing)
+	}
+	if instruction.Execute == nil {
+		return fmt.Errorf("Opcode %d has no Execute function", opcode)
+	}
+	for _, write := range instruction.Writes {
+		if write < 0 || write >= instruction.Parameters {
+			return fmt.Errorf("Opcode %d writes to parameter %d but only has %d parameters", opcode, write, instruction.Parameters)
+		}
+	}
+	if instruction.JumpTaken != nil && (instruction.JumpTarget < 0 || instruction.JumpTarget >= instruction.Parameters) {
+		return fmt.Errorf("Opcode %d jumps to parameter %d but only has %d parameters", opcode, instruction.JumpTarget, instruction.Parameters)
+	}
+
+	instructionSet[opcode] = instruction
+	return nil
+}
+
+// Returns the instruction registered for the opcode.
+func LookupInstruction(opcode int) (Instruction, bool) {
+	instruction, known := instructionSet[opcode]
+	return instruction, known
+}
+
+// Returns the opcode of the instruction with the given name.
+func LookupMnemonic(name string) (int, bool) {
+	for opcode, instruction := range instructionSet {
+		if strings.EqualFold(instruction.Name, name) {
+			return opcode, true
+		}
+	}
+	return 0, false
+}
+
+// Returns all registered opcodes in ascending order.
+func Opcodes() []int {
+	opcodes := make([]int, 0, len(instructionSet))
+	for opcode := range instructionSet {
+		opcodes = append(opcodes, opcode)
+	}
+	sort.Ints(opcodes)
+	return opcodes
+}
+
+// Adds on behalf of the executing instruction, checking for overflows if CheckOverflow is set.
+func (intCode *IntCode) add(a int, b int) (int, error) {
+	sum := a + b
+	if intCode.CheckOverflow && ((b > 0 && sum < a) || (b < 0 && sum > a)) {
+		return 0, intCode.overflow(a, b)
+	}
+	return sum, nil
+}
+
+// Multiplies on behalf of the executing instruction, checking for overflows if CheckOverflow is set.
+func (intCode *IntCode) multiply(a int, b int) (int, error) {
+	product := a * b
+	if intCode.CheckOverflow && a != 0 && (product/a != b || (a == -1 && b == math.MinInt)) {
+		return 0, intCode.overflow(a, b)
+	}
+	return product, nil
+}
+
+func (intCode *IntCode) overflow(a int, b int) error {
+	return &OverflowError{intCode.instructionPointer, intCode.Get(intCode.instructionPointer) % 100, []int{a, b}}
+}
+
+func (instruction Instruction) isWrite(parameter int) bool {
+	for _, write := range instruction.Writes {
+		if write == parameter {
+			return true
+		}
+	}
+	return false
+}
+
+// Number of values taken up by the longest known instruction.
+func maxInstructionLength() int {
+	length := 1
+	for _, instruction := range instructionSet {
+		if 1+instruction.Parameters > length {
+			length = 1 + instruction.Parameters
+		}
+	}
+	return length
+}
+
+// Splits an instruction value into its opcode and the mode of each of its parameters.
+func decodeInstruction(value int, parameters int) (opcode int, modes []int) {
+	opcode = value % 100
+	modes = make([]int, parameters)
+	paramModes := value / 100
+	for i := range modes {
+		modes[i] = paramModes % 10
+		paramModes /= 10
+	}
+	return
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,42 @@
+package intcode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckOverflow(t *testing.T) {
+	for _, program := range []string{
+		"1101,9223372036854775807,1,0,99",
+		"1101,-9223372036854775808,-1,0,99",
+		"1102,4611686018427387904,2,0,99",
+		"1102,-1,-9223372036854775808,0,99",
+	} {
+		for _, compile := range []bool{false, true} {
+			intCode := newTestIntCode(t, program)
+			intCode.CheckOverflow = true
+			var err error
+			if compile {
+				err = Compile(&intCode).Run()
+			} else {
+				err = intCode.Run()
+			}
+
+			var overflow *OverflowError
+			if !errors.As(err, &overflow) || overflow.InstructionPointer != 0 {
+				t.Errorf("%s (compiled: %v): expected an OverflowError at 0 but got %v", program, compile, err)
+			}
+		}
+
+		intCode := newTestIntCode(t, program)
+		if err := intCode.Run(); err != nil {
+			t.Errorf("%s: expected to wrap around without CheckOverflow but got %v", program, err)
+		}
+	}
+
+	intCode := newTestIntCode(t, "1102,3037000499,3037000499,0,99")
+	intCode.CheckOverflow = true
+	if err := intCode.Run(); err != nil || intCode.Get(0) != 9223372030926249001 {
+		t.Errorf("Expected the largest square to fit but got %d (%v)", intCode.Get(0), err)
+	}
+}
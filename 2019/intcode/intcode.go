@@ -0,0 +1,422 @@
+// Package intcode contains the Intcode computer that is shared between all 2019 puzzles using it.
+package intcode
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type IntCode struct {
+	code               memory
+	instructionPointer int
+	relativeBase       int
+
+	state State
+
+	// Opcode 3 reads values passed to Feed first and falls back to Input once they are used up. If no Input
+	// is connected the machine pauses in the WaitingForInput state instead.
+	// Opcode 4 writes to Output or, if no Output is connected, keeps the value until TakeOutput is called.
+	// Using the same channel as the Output of one machine and the Input of another one chains them together,
+	// which also works in a feedback loop as long as every machine runs in its own goroutine.
+	Input       <-chan int
+	Output      chan<- int
+	inputQueue  []int
+	outputQueue []int
+
+	// Stops a run with a StepLimitExceededError once this many instructions were executed. 0 means no limit.
+	MaxSteps int
+	steps    int
+	// Makes additions and multiplications fail with an OverflowError instead of silently wrapping around.
+	CheckOverflow bool
+
+	// Context of the running RunContext call, nil otherwise.
+	ctx context.Context
+
+	// Receives every executed instruction if set.
+	Tracer Tracer
+	trace  *TraceEvent
+	// Set if the executing instruction called Jump.
+	jumped bool
+	// Called by Write before memory is changed, used by CompiledProgram to notice writes to translated code.
+	beforeWrite func(address int, value int)
+	// Undo log of the executed instructions, only recorded after RecordHistory was called.
+	history []historyEntry
+
+	Debug bool
+}
+
+func NewIntCode(commaSeparated string) (IntCode, error) {
+	code, err := ParseProgram(commaSeparated)
+	if err != nil {
+		return IntCode{}, err
+	}
+
+	return IntCode{code: newMemory(code)}, nil
+}
+
+// Parses a comma separated program into the values of its memory.
+func ParseProgram(commaSeparated string) ([]int, error) {
+	split := strings.Split(strings.TrimSpace(commaSeparated), ",")
+	code := make([]int, len(split))
+
+	for i, str := range split {
+		num, err := strconv.Atoi(strings.TrimSpace(str))
+		if err != nil {
+			return nil, &ParseError{Address: i, Value: str, Err: err}
+		}
+		code[i] = num
+	}
+
+	return code, nil
+}
+
+func (intCode *IntCode) PrintDebug(additionalInfo string) {
+	if !intCode.Debug {
+		return
+	}
+
+	log.Printf(
+		"%s instructionPointer=%d relativeBase=%d current=%v",
+		additionalInfo,
+		intCode.instructionPointer,
+		intCode.relativeBase,
+		intCode.code.slice(intCode.instructionPointer, 10),
+	)
+}
+
+func (intCode *IntCode) Get(address int) int {
+	return intCode.code.get(address)
+}
+
+func (intCode *IntCode) Set(address int, value int) {
+	intCode.code.set(address, value)
+}
+
+func (intCode *IntCode) GetSlice(start int, length int) []int {
+	return intCode.code.slice(start, length)
+}
+
+func (intCode *IntCode) Clone() IntCode {
+	clone := *intCode
+	clone.code = intCode.code.clone()
+	clone.inputQueue = append([]int(nil), intCode.inputQueue...)
+	clone.outputQueue = append([]int(nil), intCode.outputQueue...)
+	if intCode.history != nil {
+		clone.history = append([]historyEntry{}, intCode.history...)
+	}
+	return clone
+}
+
+func (intCode *IntCode) State() State {
+	return intCode.state
+}
+
+func (intCode *IntCode) InstructionPointer() int {
+	return intCode.instructionPointer
+}
+
+func (intCode *IntCode) RelativeBase() int {
+	return intCode.relativeBase
+}
+
+// Queues values to be read by the input instruction before anything is read from Input.
+func (intCode *IntCode) Feed(values ...int) {
+	intCode.inputQueue = append(intCode.inputQueue, values...)
+}
+
+// Returns all values that were output since the last call and that were not sent to Output.
+func (intCode *IntCode) TakeOutput() []int {
+	output := intCode.outputQueue
+	intCode.outputQueue = nil
+	return output
+}
+
+// Reads the next input for the executing instruction: from the values queued by Feed, or blocking on Input.
+// ok is false if there is no input available yet.
+func (intCode *IntCode) ReadInput() (value int, ok bool, err error) {
+	if len(intCode.inputQueue) > 0 {
+		value = intCode.inputQueue[0]
+		intCode.inputQueue = intCode.inputQueue[1:]
+		ok = true
+	} else if intCode.Input != nil && intCode.ctx != nil {
+		select {
+		case value, ok = <-intCode.Input:
+			if !ok {
+				err = ErrInputClosed
+			}
+		case <-intCode.ctx.Done():
+			err = intCode.cancelled()
+		}
+	} else if intCode.Input != nil {
+		value, ok = <-intCode.Input
+		if !ok {
+			err = ErrInputClosed
+		}
+	}
+
+	if ok && intCode.trace != nil {
+		intCode.trace.Inputs = append(intCode.trace.Inputs, value)
+	}
+	return value, ok, err
+}
+
+// Outputs a value on behalf of the executing instruction, to Output or to the queue read by TakeOutput.
+// Returns a CancelledError if the context of RunContext is done while waiting for Output to be read.
+func (intCode *IntCode) WriteOutput(value int) error {
+	if intCode.Output == nil {
+		intCode.outputQueue = append(intCode.outputQueue, value)
+	} else if intCode.ctx != nil {
+		select {
+		case intCode.Output <- value:
+		case <-intCode.ctx.Done():
+			return intCode.cancelled()
+		}
+	} else {
+		intCode.Output <- value
+	}
+
+	if intCode.trace != nil {
+		intCode.trace.Outputs = append(intCode.trace.Outputs, value)
+	}
+	return nil
+}
+
+func (intCode *IntCode) String() string {
+	return FormatProgram(intCode.code.dense)
+}
+
+// Formats memory values as a comma separated program, the inverse of ParseProgram.
+func FormatProgram(code []int) string {
+	str := make([]string, len(code))
+	for i, value := range code {
+		str[i] = strconv.Itoa(value)
+	}
+	return strings.Join(str, ",")
+}
+
+// Reads the parameters of the current instruction in the given modes. writeParameters are the indexes of the
+// parameters that the instruction writes to, which must not be in immediate mode.
+func (intCode *IntCode) parametersForCurrentInstruction(
+	paramModes []int,
+	writeParameters ...int,
+) (parameters []int, rawParameters []int, err error) {
+	opcode := intCode.Get(intCode.instructionPointer) % 100
+	rawParameters = make([]int, len(paramModes))
+	parameters = make([]int, len(paramModes))
+
+	for i := range rawParameters {
+		address := intCode.instructionPointer + 1 + i
+		if address < 0 {
+			return nil, nil, &AddressOutOfBoundsError{intCode.instructionPointer, opcode, address}
+		}
+		rawParameters[i] = intCode.Get(address)
+	}
+
+	for i, param := range rawParameters {
+		paramMode := paramModes[i]
+		switch paramMode {
+		case 0:
+			// position mode
+			if param < 0 {
+				return nil, nil, &AddressOutOfBoundsError{intCode.instructionPointer, opcode, param}
+			}
+			parameters[i] = intCode.Get(param)
+			break
+		case 1:
+			// immediate mode: nothing changes
+			for _, writeParameter := range writeParameters {
+				if writeParameter == i {
+					return nil, nil, &ImmediateWriteError{intCode.instructionPointer, opcode, i}
+				}
+			}
+			parameters[i] = param
+			break
+		case 2:
+			// relative mode: like position mode, but relative to the relative base
+			rawParameters[i] = intCode.relativeBase + param
+			if rawParameters[i] < 0 {
+				return nil, nil, &AddressOutOfBoundsError{intCode.instructionPointer, opcode, rawParameters[i]}
+			}
+			parameters[i] = intCode.Get(rawParameters[i])
+			break
+		default:
+			return nil, nil, &InvalidParameterModeError{intCode.instructionPointer, opcode, i, paramMode}
+		}
+	}
+
+	return
+}
+
+// Writes to memory on behalf of the executing instruction. Unlike Set the write is passed to the Tracer and
+// can be undone by StepBack.
+func (intCode *IntCode) Write(address int, value int) {
+	if intCode.trace != nil {
+		intCode.trace.Writes = append(intCode.trace.Writes, MemoryWrite{address, intCode.Get(address), value})
+	}
+	if intCode.beforeWrite != nil {
+		intCode.beforeWrite(address, value)
+	}
+	intCode.Set(address, value)
+}
+
+// Continues execution at the given address instead of the instruction after the executing one.
+func (intCode *IntCode) Jump(address int) {
+	intCode.instructionPointer = address
+	intCode.jumped = true
+}
+
+func (intCode *IntCode) incrementInstructionPointerBasedOnNumberOfParameters(params []int) {
+	intCode.instructionPointer += len(params) + 1
+}
+
+// Executes the instruction at the instruction pointer and returns the state the machine is in afterwards.
+func (intCode *IntCode) RunStep() (state State, err error) {
+	if intCode.instructionPointer < 0 {
+		intCode.state = Faulted
+		return Faulted, &AddressOutOfBoundsError{intCode.instructionPointer, 0, intCode.instructionPointer}
+	}
+
+	intCode.PrintDebug("")
+	instruction := intCode.Get(intCode.instructionPointer) % 100
+	if intCode.MaxSteps > 0 && intCode.steps >= intCode.MaxSteps {
+		intCode.state = Faulted
+		return Faulted, &StepLimitExceededError{intCode.instructionPointer, instruction, intCode.steps}
+	}
+
+	// Note about params and raw: params are the parameters with the parameter mode applied to it, raw without.
+	// In most cases (e.g. calculation, comparison) you will want to use the params with parameter mode.
+	// Only use raw if you need to set an offset to update the intcode on the fly. For parameters in relative
+	// mode raw already contains the relative base, so it can be used as an address directly.
+	var params, raw []int
+
+	info, known := instructionSet[instruction]
+	if !known {
+		intCode.state = Faulted
+		return Faulted, &InvalidOpcodeError{intCode.instructionPointer, instruction}
+	}
+	_, paramModes := decodeInstruction(intCode.Get(intCode.instructionPointer), info.Parameters)
+	params, raw, err = intCode.parametersForCurrentInstruction(paramModes, info.Writes...)
+	if err != nil {
+		intCode.state = Faulted
+		return Faulted, err
+	}
+
+	relativeBase, previousState := intCode.relativeBase, intCode.state
+	if intCode.Tracer != nil || intCode.history != nil {
+		intCode.trace = &TraceEvent{
+			Step:               intCode.steps,
+			InstructionPointer: intCode.instructionPointer,
+			Opcode:             instruction,
+			Name:               info.Name,
+			Parameters:         params,
+		}
+	}
+
+	intCode.jumped = false
+	state, err = info.Execute(intCode, params, raw)
+	if err == nil && state == Running && !intCode.jumped {
+		intCode.incrementInstructionPointerBasedOnNumberOfParameters(params)
+	}
+
+	if err != nil {
+		state = Faulted
+	} else if state != WaitingForInput {
+		intCode.steps++
+		if intCode.trace != nil && intCode.Tracer != nil {
+			intCode.Tracer.Trace(*intCode.trace)
+		}
+		if intCode.trace != nil && intCode.history != nil {
+			intCode.history = append(intCode.history, historyEntry{*intCode.trace, relativeBase, previousState})
+		}
+	}
+	intCode.trace = nil
+	intCode.state = state
+	return
+}
+
+// Runs the program from the start until it halts. Programs without input / output instructions
+// (such as the ones from day 2) can use this directly.
+func (intCode *IntCode) Run() error {
+	intCode.reset()
+
+	state, err := intCode.RunUntilBlocked()
+	if err == nil && state == WaitingForInput {
+		err = ErrNoInput
+	}
+	return err
+}
+
+// Like Run, but stops with a CancelledError once the context is done, also while waiting on Input.
+func (intCode *IntCode) RunContext(ctx context.Context) error {
+	intCode.ctx = ctx
+	defer func() { intCode.ctx = nil }()
+	return intCode.Run()
+}
+
+// Checks whether the context of RunContext is done every this many steps.
+const contextCheckInterval = 1024
+
+func (intCode *IntCode) checkContext() error {
+	if intCode.ctx == nil || intCode.steps%contextCheckInterval != 0 || intCode.ctx.Err() == nil {
+		return nil
+	}
+	intCode.state = Faulted
+	return intCode.cancelled()
+}
+
+func (intCode *IntCode) cancelled() error {
+	return &CancelledError{intCode.instructionPointer, intCode.steps, intCode.ctx.Err()}
+}
+
+// Moves the machine back to the start of the program, memory is kept as it is.
+func (intCode *IntCode) reset() {
+	intCode.instructionPointer = 0
+	intCode.relativeBase = 0
+	intCode.steps = 0
+	intCode.state = Running
+	if intCode.history != nil {
+		intCode.history = intCode.history[:0]
+	}
+}
+
+// Continues running the program from the current instruction pointer until it halts, faults or waits for input.
+// A machine waiting for input can be resumed by feeding it a value and calling RunUntilBlocked again.
+func (intCode *IntCode) RunUntilBlocked() (State, error) {
+	for {
+		if err := intCode.checkContext(); err != nil {
+			return Faulted, err
+		}
+		state, err := intCode.RunStep()
+		if state != Running {
+			return state, err
+		}
+	}
+}
+
+// Runs the program from the start in a new goroutine. The returned channel receives the result once the
+// program has halted, at which point Output is closed so that readers can range over it.
+// Do not use this if Output is shared with other writers.
+func (intCode *IntCode) RunAsync() <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		err := intCode.Run()
+		if intCode.Output != nil {
+			close(intCode.Output)
+		}
+		done <- err
+	}()
+	return done
+}
+
+// Runs the program from the start with the given inputs and returns everything that was output.
+func (intCode *IntCode) RunWithInput(inputs ...int) ([]int, error) {
+	intCode.Input = nil
+	intCode.Output = nil
+	intCode.inputQueue = inputs
+	intCode.outputQueue = nil
+
+	err := intCode.Run()
+	return intCode.TakeOutput(), err
+}
@@ -0,0 +1,242 @@
+package intcode
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Examples from the puzzle description of day 2: memory after running the program.
+var day2Examples = []struct {
+	program  string
+	expected string
+}{
+	{"1,9,10,3,2,3,11,0,99,30,40,50", "3500,9,10,70,2,3,11,0,99,30,40,50"},
+	{"1,0,0,0,99", "2,0,0,0,99"},
+	{"2,3,0,3,99", "2,3,0,6,99"},
+	{"2,4,4,5,99,0", "2,4,4,5,99,9801"},
+	{"1,1,1,4,99,5,6,0,99", "30,1,1,4,2,5,6,0,99"},
+}
+
+// Examples from the puzzle descriptions of day 5: outputs for a single input.
+var day5Examples = []struct {
+	program  string
+	input    int
+	expected []int
+}{
+	{"3,0,4,0,99", 42, []int{42}},
+	{"3,9,8,9,10,9,4,9,99,-1,8", 8, []int{1}},
+	{"3,9,8,9,10,9,4,9,99,-1,8", 7, []int{0}},
+	{"3,9,7,9,10,9,4,9,99,-1,8", 7, []int{1}},
+	{"3,9,7,9,10,9,4,9,99,-1,8", 8, []int{0}},
+	{"3,3,1108,-1,8,3,4,3,99", 8, []int{1}},
+	{"3,3,1108,-1,8,3,4,3,99", 9, []int{0}},
+	{"3,3,1107,-1,8,3,4,3,99", 7, []int{1}},
+	{"3,3,1107,-1,8,3,4,3,99", 9, []int{0}},
+	{"3,12,6,12,15,1,13,14,13,4,13,99,-1,0,1,9", 0, []int{0}},
+	{"3,12,6,12,15,1,13,14,13,4,13,99,-1,0,1,9", 5, []int{1}},
+	{"3,3,1105,-1,9,1101,0,0,12,4,12,99,1", 0, []int{0}},
+	{"3,3,1105,-1,9,1101,0,0,12,4,12,99,1", 5, []int{1}},
+	{day5Larger, 7, []int{999}},
+	{day5Larger, 8, []int{1000}},
+	{day5Larger, 9, []int{1001}},
+}
+
+const day5Larger = "3,21,1008,21,8,20,1005,20,22,107,8,21,20,1006,20,31,1106,0,36,98,0,0,1002,21,125,20,4,20,1105,1,46,104," +
+	"999,1105,1,46,1101,1000,1,20,4,20,1105,1,46,98,99"
+
+// Parses a program that the test expects to be valid.
+func newTestIntCode(t testing.TB, program string) IntCode {
+	t.Helper()
+	intCode, err := NewIntCode(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return intCode
+}
+
+func parseTestProgram(t testing.TB, program string) []int {
+	t.Helper()
+	code, err := ParseProgram(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestDay2Examples(t *testing.T) {
+	for _, example := range day2Examples {
+		intCode, err := NewIntCode(example.program)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = intCode.Run()
+		if err != nil {
+			t.Errorf("%s: %v", example.program, err)
+		}
+		if intCode.String() != example.expected {
+			t.Errorf("%s: expected %s but got %s", example.program, example.expected, intCode.String())
+		}
+	}
+}
+
+func TestDay5Examples(t *testing.T) {
+	for _, example := range day5Examples {
+		for _, compile := range []bool{false, true} {
+			intCode, err := NewIntCode(example.program)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var outputs []int
+			if compile {
+				outputs, err = Compile(&intCode).RunWithInput(example.input)
+			} else {
+				outputs, err = intCode.RunWithInput(example.input)
+			}
+			if err != nil {
+				t.Errorf("%s with %d: %v", example.program, example.input, err)
+			}
+			if !reflect.DeepEqual(outputs, example.expected) {
+				t.Errorf("%s with %d (compiled: %v): expected %v but got %v", example.program, example.input, compile, example.expected, outputs)
+			}
+		}
+	}
+}
+
+func TestParameterModesAndNegativeValues(t *testing.T) {
+	for program, expected := range map[string]string{
+		"1002,4,3,4,33":   "1002,4,3,4,99",
+		"1101,100,-1,4,0": "1101,100,-1,4,99",
+	} {
+		intCode, err := NewIntCode(program)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = intCode.Run()
+		if err != nil || intCode.String() != expected {
+			t.Errorf("%s: expected %s but got %s (%v)", program, expected, intCode.String(), err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := NewIntCode("1,0,x,0,99")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Address != 2 {
+		t.Errorf("Expected a ParseError at address 2 but got %v", err)
+	}
+}
+
+func TestRuntimeErrors(t *testing.T) {
+	for program, expected := range map[string]interface{}{
+		"42":          &InvalidOpcodeError{},
+		"1,-1,0,0,99": &AddressOutOfBoundsError{},
+		"11101,1,1,0": &ImmediateWriteError{},
+		"301,0,0,0":   &InvalidParameterModeError{},
+		"1105,1,0":    &StepLimitExceededError{},
+	} {
+		intCode, err := NewIntCode(program)
+		if err != nil {
+			t.Fatal(err)
+		}
+		intCode.MaxSteps = 100
+		err = intCode.Run()
+		target := reflect.New(reflect.TypeOf(expected)).Interface()
+		if !errors.As(err, target) {
+			t.Errorf("%s: expected %T but got %v", program, expected, err)
+		}
+		if intCode.State() != Faulted {
+			t.Errorf("%s: expected the machine to be faulted but it is %s", program, intCode.State())
+		}
+	}
+
+	intCode := newTestIntCode(t, "3,0,99")
+	if err := intCode.Run(); err != ErrNoInput {
+		t.Errorf("Expected ErrNoInput but got %v", err)
+	}
+}
+
+func TestWaitingForInputResumes(t *testing.T) {
+	intCode := newTestIntCode(t, "3,0,4,0,3,0,4,0,99")
+	state, err := intCode.RunUntilBlocked()
+	if state != WaitingForInput || err != nil {
+		t.Fatalf("Expected to wait for input but got %s (%v)", state, err)
+	}
+
+	intCode.Feed(1)
+	if _, err := intCode.RunUntilBlocked(); err != nil {
+		t.Fatal(err)
+	}
+	intCode.Feed(2)
+	state, err = intCode.RunUntilBlocked()
+	if state != Halted || err != nil {
+		t.Fatalf("Expected to halt but got %s (%v)", state, err)
+	}
+	if outputs := intCode.TakeOutput(); !reflect.DeepEqual(outputs, []int{1, 2}) {
+		t.Errorf("Expected [1 2] but got %v", outputs)
+	}
+}
+
+func TestChannels(t *testing.T) {
+	intCode := newTestIntCode(t, "3,0,4,0,99")
+	input := make(chan int, 1)
+	output := make(chan int)
+	intCode.Input = input
+	intCode.Output = output
+
+	input <- 42
+	done := intCode.RunAsync()
+	if value := <-output; value != 42 {
+		t.Errorf("Expected 42 but got %d", value)
+	}
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRunContextStopsRunawayPrograms(t *testing.T) {
+	for _, compile := range []bool{false, true} {
+		intCode := newTestIntCode(t, "1105,1,0")
+		var err error
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		if compile {
+			err = Compile(&intCode).RunContext(ctx)
+		} else {
+			err = intCode.RunContext(ctx)
+		}
+		cancel()
+
+		var cancelled *CancelledError
+		if !errors.As(err, &cancelled) || !errors.Is(err, context.DeadlineExceeded) || cancelled.InstructionPointer != 0 {
+			t.Errorf("Expected a CancelledError at 0 (compiled: %v) but got %v", compile, err)
+		}
+	}
+
+	intCode := newTestIntCode(t, "3,0,99")
+	intCode.Input = make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := intCode.RunContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected waiting for input to be cancelled but got %v", err)
+	}
+
+	for _, compile := range []bool{false, true} {
+		intCode := newTestIntCode(t, "104,1,99")
+		intCode.Output = make(chan int)
+		var err error
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		if compile {
+			err = Compile(&intCode).RunContext(ctx)
+		} else {
+			err = intCode.RunContext(ctx)
+		}
+		cancel()
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected waiting for output to be cancelled (compiled: %v) but got %v", compile, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package intcode
+
+// Writes that land at most this far past the end of the program extend it, writes that are further away are
+// stored sparsely so that far away addresses do not allocate huge amounts of memory. The limit is measured from
+// the end of the original program, so that many writes just past the end cannot extend it step by step.
+const maxDenseGrowth = 4096
+
+// Memory of an intcode machine. It starts out as the program itself and grows as needed: every address that was
+// never written to reads as 0.
+type memory struct {
+	dense  []int
+	sparse map[int]int
+	// Addresses below this are stored in dense.
+	denseLimit int
+	// Incremented by every write, so that translated code can tell whether memory was changed behind its back.
+	generation int
+}
+
+func newMemory(code []int) memory {
+	return memory{dense: code, denseLimit: len(code) + maxDenseGrowth}
+}
+
+func (mem *memory) get(address int) int {
+	if address >= 0 && address < len(mem.dense) {
+		return mem.dense[address]
+	}
+	return mem.sparse[address]
+}
+
+func (mem *memory) set(address int, value int) {
+	mem.generation++
+	if address >= 0 && address < len(mem.dense) {
+		mem.dense[address] = value
+		return
+	}
+
+	if address >= 0 && address < mem.denseLimit {
+		mem.grow(address + 1)
+		mem.dense[address] = value
+		return
+	}
+
+	if mem.sparse == nil {
+		mem.sparse = make(map[int]int)
+	}
+	mem.sparse[address] = value
+}
+
+// Extends the dense part of the memory to the given length, moving sparse values that are now covered by it.
+func (mem *memory) grow(length int) {
+	start := len(mem.dense)
+	mem.dense = append(mem.dense, make([]int, length-start)...)
+	for address, value := range mem.sparse {
+		if address >= start && address < length {
+			mem.dense[address] = value
+			delete(mem.sparse, address)
+		}
+	}
+}
+
+// Number of cells that were part of the program or are directly behind it.
+func (mem *memory) len() int {
+	return len(mem.dense)
+}
+
+func (mem *memory) slice(start int, length int) []int {
+	values := make([]int, length)
+	for i := range values {
+		values[i] = mem.get(start + i)
+	}
+	return values
+}
+
+func (mem *memory) clone() memory {
+	clone := memory{dense: make([]int, len(mem.dense)), denseLimit: mem.denseLimit}
+	copy(clone.dense, mem.dense)
+	if mem.sparse != nil {
+		clone.sparse = make(map[int]int, len(mem.sparse))
+		for address, value := range mem.sparse {
+			clone.sparse[address] = value
+		}
+	}
+	return clone
+}
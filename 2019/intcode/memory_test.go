@@ -0,0 +1,26 @@
+package intcode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryBeyondProgram(t *testing.T) {
+	intCode := newTestIntCode(t, "1101,1,2,1000000,4,1000000,99")
+	outputs, err := intCode.RunWithInput()
+	if err != nil || !reflect.DeepEqual(outputs, []int{3}) {
+		t.Errorf("Expected [3] but got %v (%v)", outputs, err)
+	}
+
+	// moves the relative base by 4000 and writes behind it in a loop
+	intCode = newTestIntCode(t, "109,4000,21101,1,1,0,1105,1,0")
+	intCode.MaxSteps = 10000
+	var limitErr *StepLimitExceededError
+	if err := intCode.Run(); !errors.As(err, &limitErr) {
+		t.Errorf("Expected the step limit to be exceeded but got %v", err)
+	}
+	if intCode.code.len() > 9+maxDenseGrowth {
+		t.Errorf("Expected memory to grow by at most %d cells but it has %d", maxDenseGrowth, intCode.code.len())
+	}
+}
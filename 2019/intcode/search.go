@@ -0,0 +1,141 @@
+package intcode
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Describes which inputs to try when searching for inputs that make a program produce a certain result.
+type Search struct {
+	// Addresses that are set to the input values before every run, e.g. noun and verb at 1 and 2.
+	Addresses []int
+	// Every address takes every value from Min to Max, inclusive.
+	Min int
+	Max int
+	// Address holding the result once the program halted and the value it has to have.
+	ResultAddress int
+	Target        int
+	// Number of goroutines running programs in parallel, defaults to the number of CPUs.
+	Workers int
+	// Find all matching inputs instead of stopping at the first one.
+	All bool
+	// Budget of instructions for every run, programs exceeding it count as not matching. 0 means no limit.
+	MaxSteps int
+}
+
+// Runs copies of the program for all combinations of input values in parallel and returns the combinations
+// that produced the target result, ordered by their values. Unless All is set the search stops at the first
+// match and only a single combination is returned, the smallest of those that were found by the time all
+// workers stopped. Combinations that make the program fail are skipped.
+func (search Search) Run(parent context.Context, program IntCode) ([][]int, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	workers := search.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	combinations := make(chan []int)
+	go search.generateCombinations(ctx, combinations)
+
+	matches := make([][]int, 0)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for combination := range combinations {
+				if !search.matches(ctx, program, combination) {
+					continue
+				}
+
+				mutex.Lock()
+				matches = append(matches, combination)
+				mutex.Unlock()
+				if !search.All {
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The search was stopped from the outside before it was done.
+	if err := parent.Err(); err != nil && (search.All || len(matches) == 0) {
+		return matches, err
+	}
+
+	sort.Slice(matches, func(a, b int) bool {
+		for i := range matches[a] {
+			if matches[a][i] != matches[b][i] {
+				return matches[a][i] < matches[b][i]
+			}
+		}
+		return false
+	})
+	if !search.All && len(matches) > 1 {
+		matches = matches[:1]
+	}
+	return matches, nil
+}
+
+func (search Search) matches(ctx context.Context, program IntCode, combination []int) bool {
+	intCode := program.Clone()
+	intCode.MaxSteps = search.MaxSteps
+	for i, address := range search.Addresses {
+		intCode.Set(address, combination[i])
+	}
+
+	err := intCode.RunContext(ctx)
+	return err == nil && intCode.Get(search.ResultAddress) == search.Target
+}
+
+// Sends every combination of values to the channel and closes it once done or once the context is cancelled.
+func (search Search) generateCombinations(ctx context.Context, combinations chan<- []int) {
+	defer close(combinations)
+	if search.Max < search.Min {
+		return
+	}
+
+	current := make([]int, len(search.Addresses))
+	for i := range current {
+		current[i] = search.Min
+	}
+
+	for {
+		combination := make([]int, len(current))
+		copy(combination, current)
+		select {
+		case combinations <- combination:
+		case <-ctx.Done():
+			return
+		}
+
+		// Count up like an odometer, the last address changes fastest.
+		i := len(current) - 1
+		for ; i >= 0; i-- {
+			if current[i] < search.Max {
+				current[i]++
+				break
+			}
+			current[i] = search.Min
+		}
+		if i < 0 {
+			return
+		}
+	}
+}
+
+// Solves the search with a symbolic run of the program instead of running it for every combination, see
+// SymbolicRun. This works for huge ranges of inputs but only for programs that add and multiply.
+func (search Search) Solve(program IntCode) ([][]int, error) {
+	polynomial, err := SymbolicRun(program, search.Addresses, search.ResultAddress)
+	if err != nil {
+		return nil, err
+	}
+	return polynomial.Solve(search.Target, search.Min, search.Max, search.All)
+}
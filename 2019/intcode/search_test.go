@@ -0,0 +1,57 @@
+package intcode
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// Searching by running the program and solving it symbolically have to find the same inputs.
+func TestSearch(t *testing.T) {
+	intCode := newTestIntCode(t, "1,9,10,0,2,0,11,0,99,0,0,3")
+	search := Search{Addresses: []int{9, 10}, Min: 0, Max: 9, ResultAddress: 0, Target: 21, All: true}
+	expected := [][]int{{0, 7}, {1, 6}, {2, 5}, {3, 4}, {4, 3}, {5, 2}, {6, 1}, {7, 0}}
+
+	results, err := search.Run(context.Background(), intCode)
+	if err != nil || !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected %v but got %v (%v)", expected, results, err)
+	}
+	results, err = search.Solve(intCode)
+	if err != nil || !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected %v from solving but got %v (%v)", expected, results, err)
+	}
+
+	search.All = false
+	for i := 0; i < 20; i++ {
+		results, err = search.Run(context.Background(), intCode)
+		if err != nil || len(results) != 1 {
+			t.Fatalf("Expected a single match but got %v (%v)", results, err)
+		}
+	}
+}
+
+func TestSearchNonLinear(t *testing.T) {
+	// [0] = [9] + [10]*[10]
+	intCode, err := NewIntCode("2,10,10,11,1,9,11,0,99,0,0,0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	search := Search{Addresses: []int{9, 10}, Min: 0, Max: 4, ResultAddress: 0, Target: 4}
+
+	for _, all := range []bool{false, true} {
+		search.All = all
+		expected := [][]int{{0, 2}, {3, 1}, {4, 0}}
+		if !all {
+			expected = expected[:1]
+		}
+
+		results, err := search.Run(context.Background(), intCode)
+		if err != nil || !reflect.DeepEqual(results, expected) {
+			t.Errorf("Expected %v but got %v (%v)", expected, results, err)
+		}
+		results, err = search.Solve(intCode)
+		if err != nil || !reflect.DeepEqual(results, expected) {
+			t.Errorf("Expected %v from solving but got %v (%v)", expected, results, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package intcode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Version of the snapshot format written by SaveSnapshot. Increase it whenever the format changes in a way that
+// older versions cannot be read the same way anymore.
+const snapshotVersion = 1
+
+// Everything needed to resume a machine at the exact step it was saved at.
+type snapshot struct {
+	Version      int         `json:"version"`
+	Memory       string      `json:"memory"`
+	SparseMemory map[int]int `json:"sparseMemory,omitempty"`
+	// Addresses below this are stored densely, so that memory cannot grow further after loading a snapshot.
+	// Snapshots without it start counting from the saved memory.
+	DenseLimit         int   `json:"denseLimit,omitempty"`
+	InstructionPointer int   `json:"instructionPointer"`
+	RelativeBase       int   `json:"relativeBase"`
+	State              State `json:"state"`
+	Steps              int   `json:"steps"`
+	InputQueue         []int `json:"inputQueue"`
+	OutputQueue        []int `json:"outputQueue"`
+}
+
+// Writes the complete state of the machine as JSON: memory, registers and the values that were fed but not read
+// yet or output but not taken yet. Values waiting in the Input or Output channels are not part of the snapshot.
+func (intCode *IntCode) SaveSnapshot(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot{
+		Version:            snapshotVersion,
+		Memory:             FormatProgram(intCode.code.dense),
+		SparseMemory:       intCode.code.sparse,
+		DenseLimit:         intCode.code.denseLimit,
+		InstructionPointer: intCode.instructionPointer,
+		RelativeBase:       intCode.relativeBase,
+		State:              intCode.state,
+		Steps:              intCode.steps,
+		InputQueue:         intCode.inputQueue,
+		OutputQueue:        intCode.outputQueue,
+	})
+}
+
+// Reads a machine written by SaveSnapshot. Settings that are not part of the state, like Input, Output, MaxSteps
+// or the Tracer have to be set again.
+func LoadSnapshot(reader io.Reader) (IntCode, error) {
+	var saved snapshot
+	err := json.NewDecoder(reader).Decode(&saved)
+	if err != nil {
+		return IntCode{}, err
+	}
+	if saved.Version != snapshotVersion {
+		return IntCode{}, fmt.Errorf("Unsupported snapshot version %d, expected %d", saved.Version, snapshotVersion)
+	}
+
+	code := make([]int, 0)
+	if saved.Memory != "" {
+		code, err = ParseProgram(saved.Memory)
+		if err != nil {
+			return IntCode{}, err
+		}
+	}
+
+	intCode := IntCode{
+		code:               newMemory(code),
+		instructionPointer: saved.InstructionPointer,
+		relativeBase:       saved.RelativeBase,
+		state:              saved.State,
+		steps:              saved.Steps,
+		inputQueue:         saved.InputQueue,
+		outputQueue:        saved.OutputQueue,
+	}
+	if saved.DenseLimit > 0 {
+		intCode.code.denseLimit = saved.DenseLimit
+	}
+	for address, value := range saved.SparseMemory {
+		intCode.code.set(address, value)
+	}
+
+	return intCode, nil
+}
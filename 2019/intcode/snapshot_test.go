@@ -0,0 +1,55 @@
+package intcode
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	intCode := newTestIntCode(t, day5Larger)
+	if _, err := intCode.RunUntilBlocked(); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.Buffer{}
+	if err := intCode.SaveSnapshot(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSnapshot(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded.Feed(9)
+	if _, err := loaded.RunUntilBlocked(); err != nil {
+		t.Fatal(err)
+	}
+	if outputs := loaded.TakeOutput(); !reflect.DeepEqual(outputs, []int{1001}) {
+		t.Errorf("Expected [1001] but got %v", outputs)
+	}
+}
+
+func TestSnapshotKeepsMemoryLimit(t *testing.T) {
+	intCode, err := NewIntCode("99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intCode.Set(maxDenseGrowth-1, 1)
+
+	for i := 0; i < 3; i++ {
+		buffer := bytes.Buffer{}
+		if err := intCode.SaveSnapshot(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		intCode, err = LoadSnapshot(&buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		intCode.Set(intCode.code.len()+maxDenseGrowth-1, 1)
+	}
+
+	if intCode.code.len() > 1+maxDenseGrowth {
+		t.Errorf("Expected memory to grow by at most %d cells but it has %d", maxDenseGrowth, intCode.code.len())
+	}
+}
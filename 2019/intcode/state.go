@@ -0,0 +1,30 @@
+package intcode
+
+// State describes what a machine is doing after its last step.
+type State int
+
+const (
+	// The machine can execute its next instruction.
+	Running State = iota
+	// The machine reached an input instruction but no input is available yet. Feed it a value and
+	// resume it with RunUntilBlocked.
+	WaitingForInput
+	// The machine executed opcode 99.
+	Halted
+	// The machine encountered an error and cannot continue.
+	Faulted
+)
+
+func (state State) String() string {
+	switch state {
+	case Running:
+		return "running"
+	case WaitingForInput:
+		return "waiting for input"
+	case Halted:
+		return "halted"
+	case Faulted:
+		return "faulted"
+	}
+	return "unknown"
+}
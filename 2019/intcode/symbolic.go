@@ -0,0 +1,488 @@
+package intcode
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Returned by SymbolicRun if the result depends on a value that was read from an address that is only known
+// once the symbolic inputs have concrete values.
+var ErrUnknownResult = errors.New("The result depends on memory read from a symbolic address")
+
+// Polynomial over the symbolic inputs of a program with integer coefficients. Variable i stands for the value at
+// the i-th address passed to SymbolicRun.
+type Polynomial struct {
+	addresses []int
+	terms     map[string]monomial
+}
+
+type monomial struct {
+	exponents   []int
+	coefficient int
+}
+
+func constantPolynomial(addresses []int, value int) *Polynomial {
+	polynomial := &Polynomial{addresses: addresses, terms: make(map[string]monomial)}
+	polynomial.addTerm(make([]int, len(addresses)), value)
+	return polynomial
+}
+
+func variablePolynomial(addresses []int, variable int) *Polynomial {
+	polynomial := &Polynomial{addresses: addresses, terms: make(map[string]monomial)}
+	exponents := make([]int, len(addresses))
+	exponents[variable] = 1
+	polynomial.addTerm(exponents, 1)
+	return polynomial
+}
+
+func (polynomial *Polynomial) addTerm(exponents []int, coefficient int) {
+	key := fmt.Sprint(exponents)
+	term, exists := polynomial.terms[key]
+	if !exists {
+		term = monomial{exponents: exponents}
+	}
+	term.coefficient += coefficient
+
+	if term.coefficient == 0 {
+		delete(polynomial.terms, key)
+	} else {
+		polynomial.terms[key] = term
+	}
+}
+
+func (polynomial *Polynomial) add(other *Polynomial) *Polynomial {
+	sum := constantPolynomial(polynomial.addresses, 0)
+	for _, term := range polynomial.terms {
+		sum.addTerm(term.exponents, term.coefficient)
+	}
+	for _, term := range other.terms {
+		sum.addTerm(term.exponents, term.coefficient)
+	}
+	return sum
+}
+
+func (polynomial *Polynomial) multiply(other *Polynomial) *Polynomial {
+	product := constantPolynomial(polynomial.addresses, 0)
+	for _, a := range polynomial.terms {
+		for _, b := range other.terms {
+			exponents := make([]int, len(a.exponents))
+			for i := range exponents {
+				exponents[i] = a.exponents[i] + b.exponents[i]
+			}
+			product.addTerm(exponents, a.coefficient*b.coefficient)
+		}
+	}
+	return product
+}
+
+// Returns the value of the polynomial if it does not depend on any variable.
+func (polynomial *Polynomial) constant() (value int, ok bool) {
+	for _, term := range polynomial.terms {
+		if term.degree() > 0 {
+			return 0, false
+		}
+		value = term.coefficient
+	}
+	return value, true
+}
+
+func (term monomial) degree() int {
+	degree := 0
+	for _, exponent := range term.exponents {
+		degree += exponent
+	}
+	return degree
+}
+
+// Evaluates the polynomial with the given values for its variables.
+func (polynomial *Polynomial) Evaluate(values []int) int {
+	result := 0
+	for _, term := range polynomial.terms {
+		product := term.coefficient
+		for i, exponent := range term.exponents {
+			for j := 0; j < exponent; j++ {
+				product *= values[i]
+			}
+		}
+		result += product
+	}
+	return result
+}
+
+// Formats the polynomial with the variables named after their addresses, e.g. 460800*[1] + [2] + 337061.
+func (polynomial *Polynomial) String() string {
+	terms := make([]monomial, 0, len(polynomial.terms))
+	for _, term := range polynomial.terms {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(a, b int) bool {
+		if terms[a].degree() != terms[b].degree() {
+			return terms[a].degree() > terms[b].degree()
+		}
+		return fmt.Sprint(terms[a].exponents) > fmt.Sprint(terms[b].exponents)
+	})
+
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		factors := make([]string, 0)
+		if term.coefficient != 1 || term.degree() == 0 {
+			factors = append(factors, strconv.Itoa(term.coefficient))
+		}
+		for i, exponent := range term.exponents {
+			for j := 0; j < exponent; j++ {
+				factors = append(factors, fmt.Sprintf("[%d]", polynomial.addresses[i]))
+			}
+		}
+		parts = append(parts, strings.Join(factors, "*"))
+	}
+	if len(parts) == 0 {
+		return "0"
+	}
+	return strings.Join(parts, " + ")
+}
+
+// Value of a memory cell during a symbolic run. A nil polynomial means the value is unknown because it was read
+// from an address that depends on the symbolic inputs.
+type symbolicValue struct {
+	polynomial *Polynomial
+}
+
+// Runs the program with symbolic values at the given addresses instead of concrete numbers and returns the value
+// at resultAddress as a polynomial over them once the program halted. Only programs consisting of additions and
+// multiplications are supported, as there is no way to decide a jump or comparison on a symbolic value.
+func SymbolicRun(program IntCode, addresses []int, resultAddress int) (*Polynomial, error) {
+	cells := make(map[int]symbolicValue)
+	read := func(address int) symbolicValue {
+		if value, exists := cells[address]; exists {
+			return value
+		}
+		return symbolicValue{constantPolynomial(addresses, program.Get(address))}
+	}
+	for i, address := range addresses {
+		cells[address] = symbolicValue{variablePolynomial(addresses, i)}
+	}
+
+	instructionPointer := 0
+	for steps := 0; program.MaxSteps == 0 || steps < program.MaxSteps; steps++ {
+		instructionValue := read(instructionPointer)
+		if instructionValue.polynomial == nil {
+			return nil, fmt.Errorf("The instruction at position %d depends on the symbolic inputs", instructionPointer)
+		}
+		instruction, ok := instructionValue.polynomial.constant()
+		if !ok {
+			return nil, fmt.Errorf("The instruction at position %d depends on the symbolic inputs", instructionPointer)
+		}
+
+		opcode := instruction % 100
+		if opcode == 99 {
+			result := read(resultAddress)
+			if result.polynomial == nil {
+				return nil, ErrUnknownResult
+			}
+			return result.polynomial, nil
+		}
+		if opcode != 1 && opcode != 2 {
+			return nil, fmt.Errorf(
+				"Intcode %d at position %d is not supported by symbolic runs, only 1, 2 and 99 are",
+				opcode,
+				instructionPointer,
+			)
+		}
+
+		_, modes := decodeInstruction(instruction, 3)
+		operands := make([]symbolicValue, 2)
+		for i := range operands {
+			parameter := read(instructionPointer + 1 + i)
+			switch modes[i] {
+			case immediateMode:
+				operands[i] = parameter
+			case positionMode:
+				address, known := parameter.address()
+				if !known {
+					operands[i] = symbolicValue{}
+				} else if address < 0 {
+					return nil, &AddressOutOfBoundsError{instructionPointer, opcode, address}
+				} else {
+					operands[i] = read(address)
+				}
+			default:
+				return nil, &InvalidParameterModeError{instructionPointer, opcode, i, modes[i]}
+			}
+		}
+
+		if modes[2] != positionMode {
+			return nil, &InvalidParameterModeError{instructionPointer, opcode, 2, modes[2]}
+		}
+		target, known := read(instructionPointer + 3).address()
+		if !known {
+			return nil, fmt.Errorf("The instruction at position %d writes to an address depending on the symbolic inputs", instructionPointer)
+		}
+		if target < 0 {
+			return nil, &AddressOutOfBoundsError{instructionPointer, opcode, target}
+		}
+
+		result := symbolicValue{}
+		if operands[0].polynomial != nil && operands[1].polynomial != nil {
+			if opcode == 1 {
+				result.polynomial = operands[0].polynomial.add(operands[1].polynomial)
+			} else {
+				result.polynomial = operands[0].polynomial.multiply(operands[1].polynomial)
+			}
+		}
+		cells[target] = result
+		instructionPointer += 4
+	}
+
+	return nil, &StepLimitExceededError{instructionPointer, read(instructionPointer).constantOr(0) % 100, program.MaxSteps}
+}
+
+// The value as an address, which is only known if it does not depend on the symbolic inputs.
+func (value symbolicValue) address() (int, bool) {
+	if value.polynomial == nil {
+		return 0, false
+	}
+	return value.polynomial.constant()
+}
+
+func (value symbolicValue) constantOr(fallback int) int {
+	if constant, ok := value.address(); ok {
+		return constant
+	}
+	return fallback
+}
+
+// Finds all values from min to max (inclusive) for the variables that make the polynomial equal the target,
+// ordered by their values. Unless all is set only the first solution is returned.
+// Polynomials that are linear are solved directly for the last two variables, so their range can be huge. All
+// other polynomials need at least one variable that only appears linearly, which is solved for while all others
+// are enumerated.
+func (polynomial *Polynomial) Solve(target int, min int, max int, all bool) ([][]int, error) {
+	variables := len(polynomial.addresses)
+	if variables == 0 {
+		if polynomial.Evaluate(nil) == target {
+			return [][]int{{}}, nil
+		}
+		return [][]int{}, nil
+	}
+
+	solved := -1
+	for variable := variables - 1; variable >= 0 && solved < 0; variable-- {
+		if polynomial.isLinearIn(variable) {
+			solved = variable
+		}
+	}
+	if solved < 0 {
+		return nil, fmt.Errorf("Cannot solve %s, every variable appears with a higher power", polynomial)
+	}
+
+	solver := polynomialSolver{polynomial: polynomial, target: target, min: min, max: max, all: all}
+	if polynomial.isLinear() && variables >= 2 {
+		solver.inOrder = true
+		solver.enumerate(make([]int, 0, variables), variables-2, solver.solveLastTwoLinear)
+	} else {
+		solver.inOrder = solved == variables-1
+		solver.enumerate(make([]int, 0, variables), variables-1, func(values []int) {
+			solver.solveFor(values, solved)
+		})
+	}
+
+	sort.Slice(solver.solutions, func(a, b int) bool {
+		for i := range solver.solutions[a] {
+			if solver.solutions[a][i] != solver.solutions[b][i] {
+				return solver.solutions[a][i] < solver.solutions[b][i]
+			}
+		}
+		return false
+	})
+	if !all && len(solver.solutions) > 1 {
+		solver.solutions = solver.solutions[:1]
+	}
+	return solver.solutions, nil
+}
+
+func (polynomial *Polynomial) isLinearIn(variable int) bool {
+	for _, term := range polynomial.terms {
+		if term.exponents[variable] > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func (polynomial *Polynomial) isLinear() bool {
+	for _, term := range polynomial.terms {
+		if term.degree() > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+type polynomialSolver struct {
+	polynomial *Polynomial
+	target     int
+	min        int
+	max        int
+	all        bool
+	solutions  [][]int
+	// Set if solutions are found in ascending order, so that the first one is the smallest. Otherwise all
+	// solutions have to be found before the first one is known.
+	inOrder bool
+}
+
+func (solver *polynomialSolver) done() bool {
+	return !solver.all && solver.inOrder && len(solver.solutions) > 0
+}
+
+func (solver *polynomialSolver) addSolution(values []int) {
+	solution := make([]int, len(values))
+	copy(solution, values)
+	solver.solutions = append(solver.solutions, solution)
+}
+
+// Calls solve with every combination of values for the given number of leading variables.
+func (solver *polynomialSolver) enumerate(values []int, count int, solve func(values []int)) {
+	if len(values) == count {
+		solve(values)
+		return
+	}
+	for value := solver.min; value <= solver.max && !solver.done(); value++ {
+		solver.enumerate(append(values, value), count, solve)
+	}
+}
+
+// Solves for a single variable that appears linearly, with all other variables set to the given values in order.
+func (solver *polynomialSolver) solveFor(others []int, variable int) {
+	values := make([]int, 0, len(others)+1)
+	values = append(values, others[:variable]...)
+	values = append(values, 0)
+	values = append(values, others[variable:]...)
+
+	// The polynomial is a*x + b for the solved variable x.
+	b := solver.polynomial.Evaluate(values)
+	values[variable] = 1
+	a := solver.polynomial.Evaluate(values) - b
+
+	if a == 0 {
+		if b != solver.target {
+			return
+		}
+		for x := solver.min; x <= solver.max && !solver.done(); x++ {
+			values[variable] = x
+			solver.addSolution(values)
+		}
+		return
+	}
+
+	if (solver.target-b)%a != 0 {
+		return
+	}
+	x := (solver.target - b) / a
+	if x >= solver.min && x <= solver.max {
+		values[variable] = x
+		solver.addSolution(values)
+	}
+}
+
+// Solves a*x + b*y = target - c for the last two variables of a linear polynomial with the extended euclidean
+// algorithm, the other variables are already set to the given values.
+func (solver *polynomialSolver) solveLastTwoLinear(others []int) {
+	values := append(append(make([]int, 0, len(others)+2), others...), 0, 0)
+	x, y := len(values)-2, len(values)-1
+
+	c := solver.polynomial.Evaluate(values)
+	values[x] = 1
+	a := solver.polynomial.Evaluate(values) - c
+	values[x] = 0
+	values[y] = 1
+	b := solver.polynomial.Evaluate(values) - c
+	values[y] = 0
+	rest := solver.target - c
+
+	if a == 0 || b == 0 {
+		// Only one of them matters, which leaves a single variable to solve for.
+		solved := y
+		if b == 0 {
+			solved = x
+		}
+		free := x + y - solved
+		for value := solver.min; value <= solver.max && !solver.done(); value++ {
+			values[free] = value
+			solver.solveFor(append(append([]int{}, values[:solved]...), values[solved+1:]...), solved)
+		}
+		return
+	}
+
+	gcd, factorA, factorB := extendedGCD(a, b)
+	if rest%gcd != 0 {
+		return
+	}
+
+	// All solutions are x = x0 + k*stepX and y = y0 + k*stepY.
+	x0, y0 := factorA*(rest/gcd), factorB*(rest/gcd)
+	stepX, stepY := b/gcd, -a/gcd
+
+	minK, maxK := stepRange(x0, stepX, solver.min, solver.max)
+	minKY, maxKY := stepRange(y0, stepY, solver.min, solver.max)
+	if minKY > minK {
+		minK = minKY
+	}
+	if maxKY < maxK {
+		maxK = maxKY
+	}
+
+	// Go through k in the direction that makes x grow so the solutions come out in order.
+	first, last, direction := minK, maxK, 1
+	if stepX < 0 {
+		first, last, direction = maxK, minK, -1
+	}
+	for k := first; (k-last)*direction <= 0 && !solver.done(); k += direction {
+		values[x] = x0 + k*stepX
+		values[y] = y0 + k*stepY
+		solver.addSolution(values)
+	}
+}
+
+// Returns the gcd of a and b along with factors so that a*factorA + b*factorB = gcd. The gcd is positive.
+func extendedGCD(a int, b int) (gcd int, factorA int, factorB int) {
+	oldR, r := a, b
+	oldS, s := 1, 0
+	oldT, t := 0, 1
+	for r != 0 {
+		quotient := oldR / r
+		oldR, r = r, oldR-quotient*r
+		oldS, s = s, oldS-quotient*s
+		oldT, t = t, oldT-quotient*t
+	}
+	if oldR < 0 {
+		return -oldR, -oldS, -oldT
+	}
+	return oldR, oldS, oldT
+}
+
+// Range of k so that start + k*step lies within min and max, step must not be 0.
+func stepRange(start int, step int, min int, max int) (minK int, maxK int) {
+	if step > 0 {
+		return ceilDiv(min-start, step), floorDiv(max-start, step)
+	}
+	return ceilDiv(max-start, step), floorDiv(min-start, step)
+}
+
+func floorDiv(a int, b int) int {
+	quotient := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		quotient--
+	}
+	return quotient
+}
+
+func ceilDiv(a int, b int) int {
+	quotient := a / b
+	if (a%b != 0) && ((a < 0) == (b < 0)) {
+		quotient++
+	}
+	return quotient
+}
@@ -0,0 +1,142 @@
+package intcode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Receives every instruction executed by a machine that has it set as its Tracer.
+type Tracer interface {
+	Trace(event TraceEvent)
+}
+
+// A single executed instruction.
+type TraceEvent struct {
+	Step               int    `json:"step"`
+	InstructionPointer int    `json:"ip"`
+	Opcode             int    `json:"opcode"`
+	Name               string `json:"name"`
+	// Parameters with their parameter mode applied, as seen by the instruction before it executed.
+	Parameters []int         `json:"params"`
+	Writes     []MemoryWrite `json:"writes,omitempty"`
+	Inputs     []int         `json:"inputs,omitempty"`
+	Outputs    []int         `json:"outputs,omitempty"`
+}
+
+type MemoryWrite struct {
+	Address  int `json:"address"`
+	OldValue int `json:"old"`
+	NewValue int `json:"new"`
+}
+
+// Passes every event on to all of the given tracers.
+func MultiTracer(tracers ...Tracer) Tracer {
+	return multiTracer(tracers)
+}
+
+type multiTracer []Tracer
+
+func (tracers multiTracer) Trace(event TraceEvent) {
+	for _, tracer := range tracers {
+		tracer.Trace(event)
+	}
+}
+
+// Writes every event as a line of JSON. Writing stops at the first error, which is available through Err.
+type JSONTracer struct {
+	encoder *json.Encoder
+	err     error
+}
+
+func NewJSONTracer(writer io.Writer) *JSONTracer {
+	return &JSONTracer{encoder: json.NewEncoder(writer)}
+}
+
+func (tracer *JSONTracer) Trace(event TraceEvent) {
+	if tracer.err == nil {
+		tracer.err = tracer.encoder.Encode(event)
+	}
+}
+
+func (tracer *JSONTracer) Err() error {
+	return tracer.err
+}
+
+// Counts how often every address and every opcode was executed.
+type Profile struct {
+	Steps        int
+	AddressHits  map[int]int
+	OpcodeCounts map[int]int
+	names        map[int]string
+}
+
+func NewProfile() *Profile {
+	return &Profile{
+		AddressHits:  make(map[int]int),
+		OpcodeCounts: make(map[int]int),
+		names:        make(map[int]string),
+	}
+}
+
+func (profile *Profile) Trace(event TraceEvent) {
+	profile.Steps++
+	profile.AddressHits[event.InstructionPointer]++
+	profile.OpcodeCounts[event.Opcode]++
+	profile.names[event.Opcode] = event.Name
+}
+
+// Writes the opcode counts and the most executed addresses in a human readable table.
+func (profile *Profile) WriteSummary(writer io.Writer, topAddresses int) error {
+	_, err := fmt.Fprintf(writer, "%d instructions executed\n\nopcode  name        count       %%\n", profile.Steps)
+	if err != nil {
+		return err
+	}
+	for _, opcode := range sortedByCount(profile.OpcodeCounts) {
+		count := profile.OpcodeCounts[opcode]
+		_, err = fmt.Fprintf(writer, "%6d  %-6s %10d  %5.1f%%\n", opcode, profile.names[opcode], count, profile.percentage(count))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(writer, "\naddress       hits       %%\n")
+	if err != nil {
+		return err
+	}
+	for i, address := range sortedByCount(profile.AddressHits) {
+		if i >= topAddresses {
+			break
+		}
+		count := profile.AddressHits[address]
+		_, err = fmt.Fprintf(writer, "%7d %10d  %5.1f%%\n", address, count, profile.percentage(count))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (profile *Profile) percentage(count int) float64 {
+	if profile.Steps == 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(profile.Steps)
+}
+
+// Keys of the map, highest count first and lowest key first for equal counts.
+func sortedByCount(counts map[int]int) []int {
+	keys := make([]int, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if counts[keys[a]] != counts[keys[b]] {
+			return counts[keys[a]] > counts[keys[b]]
+		}
+		return keys[a] < keys[b]
+	})
+	return keys
+}
@@ -0,0 +1,45 @@
+package intcode
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	intCode := newTestIntCode(t, day5Larger)
+	profile := NewProfile()
+	var buffer bytes.Buffer
+	tracer := NewJSONTracer(&buffer)
+	intCode.Tracer = MultiTracer(profile, tracer)
+
+	outputs, err := intCode.RunWithInput(8)
+	if err != nil || !reflect.DeepEqual(outputs, []int{1000}) {
+		t.Fatalf("Expected [1000] but got %v (%v)", outputs, err)
+	}
+
+	// IN, EQ, JT to 22, MUL, OUT, JT to 46, HALT
+	expectedOpcodes := map[int]int{3: 1, 8: 1, 5: 2, 2: 1, 4: 1, 99: 1}
+	expectedHits := map[int]int{0: 1, 2: 1, 6: 1, 22: 1, 26: 1, 28: 1, 46: 1}
+	if profile.Steps != 7 || !reflect.DeepEqual(profile.OpcodeCounts, expectedOpcodes) ||
+		!reflect.DeepEqual(profile.AddressHits, expectedHits) {
+		t.Errorf("Unexpected profile: %d steps, opcodes %v, addresses %v", profile.Steps, profile.OpcodeCounts, profile.AddressHits)
+	}
+
+	decoder := json.NewDecoder(&buffer)
+	events := make([]TraceEvent, 0)
+	for decoder.More() {
+		var event TraceEvent
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	if tracer.Err() != nil || len(events) != 7 {
+		t.Fatalf("Expected 7 trace events but got %d (%v)", len(events), tracer.Err())
+	}
+	if !reflect.DeepEqual(events[0].Inputs, []int{8}) || !reflect.DeepEqual(events[4].Outputs, []int{1000}) {
+		t.Errorf("Expected the input and output in the trace but got %+v and %+v", events[0], events[4])
+	}
+}
@@ -0,0 +1,155 @@
+package intcode
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Severity int
+
+const (
+	// Something that is likely a mistake but may be intended, e.g. a program that modifies itself.
+	Warning Severity = iota
+	// Something that makes the machine fault once execution reaches it.
+	Error
+)
+
+func (severity Severity) String() string {
+	if severity == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Problem that Vet found in a program.
+type Finding struct {
+	// Address of the instruction the finding is about.
+	Address  int
+	Severity Severity
+	Message  string
+}
+
+func (finding Finding) String() string {
+	return fmt.Sprintf("%6d  %s: %s", finding.Address, finding.Severity, finding.Message)
+}
+
+// Checks a program without running it. Starting at address 0 every instruction that can be reached by stepping
+// through the program and following jumps to constant addresses is checked for unknown opcodes, invalid parameter
+// modes, writes in immediate mode, negative constant addresses and jumps outside of the program. Writes to constant
+// addresses inside of reachable instructions are reported as warnings, as the program modifies its own code.
+// Jumps to addresses that are only known at runtime cannot be followed, so code only reachable through them is
+// not checked.
+func Vet(code []int) []Finding {
+	findings := make([]Finding, 0)
+	report := func(address int, severity Severity, format string, args ...interface{}) {
+		findings = append(findings, Finding{address, severity, fmt.Sprintf(format, args...)})
+	}
+
+	// Last address of every visited instruction.
+	ends := make(map[int]int)
+	starts := make(map[int]bool)
+	pending := []int{0}
+	for len(pending) > 0 {
+		address := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if starts[address] {
+			continue
+		}
+		starts[address] = true
+
+		if address >= len(code) {
+			report(address, Error, "Execution runs past the end of the program")
+			continue
+		}
+		ends[address] = address
+		info, known := instructionSet[code[address]%100]
+		if !known {
+			report(address, Error, "Unknown opcode %d", code[address]%100)
+			continue
+		}
+		if address+info.Parameters >= len(code) {
+			report(address, Error, "%s is missing parameters at the end of the program", info.Name)
+			continue
+		}
+
+		ends[address] = address + info.Parameters
+		valid := true
+		_, modes := decodeInstruction(code[address], info.Parameters)
+		for i, mode := range modes {
+			value := code[address+1+i]
+			switch {
+			case mode != positionMode && mode != immediateMode && mode != relativeMode:
+				report(address, Error, "Invalid mode %d for parameter %d of %s", mode, i+1, info.Name)
+				valid = false
+			case mode == immediateMode && info.isWrite(i):
+				report(address, Error, "Parameter %d of %s is written to in immediate mode", i+1, info.Name)
+				valid = false
+			case mode == positionMode && value < 0:
+				report(address, Error, "Parameter %d of %s refers to negative address %d", i+1, info.Name, value)
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		for _, next := range nextInstructions(code, address, info, modes) {
+			if next < 0 || (next >= len(code) && next != address+1+info.Parameters) {
+				report(address, Error, "%s jumps to %d, which is outside of the program", info.Name, next)
+				continue
+			}
+			pending = append(pending, next)
+		}
+	}
+
+	// Only now that all instructions are known can writes into them be detected. Instructions writing to their
+	// own parameters are left out, those values have already been read.
+	isCode := make(map[int]bool)
+	for address, end := range ends {
+		for i := address; i <= end; i++ {
+			isCode[i] = true
+		}
+	}
+	// Addresses of the instructions writing to each constant address.
+	writers := make(map[int][]int)
+	for address := range starts {
+		info, modes, ok := decodeAt(code, address)
+		if !ok || address+info.Parameters >= len(code) {
+			continue
+		}
+		for _, write := range info.Writes {
+			target := code[address+1+write]
+			if modes[write] != positionMode || (target > address && target <= ends[address]) {
+				continue
+			}
+			writers[target] = append(writers[target], address)
+			if isCode[target] {
+				report(address, Warning, "%s writes to address %d, which is part of the program's code", info.Name, target)
+			}
+		}
+	}
+
+	// Errors in instructions that other instructions modify may not happen at runtime.
+	for i, finding := range findings {
+		if finding.Severity == Error && isModifiedByOthers(finding.Address, ends[finding.Address], writers) {
+			findings[i].Severity = Warning
+			findings[i].Message += ", but the instruction is modified at runtime"
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Address < findings[j].Address
+	})
+	return findings
+}
+
+func isModifiedByOthers(start int, end int, writers map[int][]int) bool {
+	for address := start; address <= end; address++ {
+		for _, writer := range writers[address] {
+			if writer != start {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package intcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVet(t *testing.T) {
+	// ADD with an immediate write at 0
+	code := parseTestProgram(t, "11101,1,2,3,99")
+	findings := Vet(code)
+	if len(findings) != 1 || findings[0].Address != 0 || findings[0].Severity != Error ||
+		!strings.Contains(findings[0].Message, "immediate mode") {
+		t.Errorf("Expected an immediate write at 0 but got %v", findings)
+	}
+
+	// JT to 100 at 4
+	code = parseTestProgram(t, "1101,1,2,8,1105,1,100,99,0")
+	findings = Vet(code)
+	if len(findings) != 1 || findings[0].Address != 4 || findings[0].Severity != Error ||
+		!strings.Contains(findings[0].Message, "jumps to 100") {
+		t.Errorf("Expected a jump outside of the program at 4 but got %v", findings)
+	}
+}
@@ -0,0 +1,121 @@
+package world
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"sort"
+)
+
+// Colors used to export tiles as images, tiles missing from it are black.
+type Colors map[int]color.Color
+
+var ArcadeColors = Colors{
+	Empty:  color.Black,
+	Wall:   color.Gray{0x80},
+	Block:  color.RGBA{0x40, 0x80, 0xff, 0xff},
+	Paddle: color.White,
+	Ball:   color.RGBA{0xff, 0xc0, 0x00, 0xff},
+}
+
+var HullColors = Colors{Black: color.Black, White: color.White}
+
+// Renders the world as an image with scale pixels per point.
+func (world *World) Image(colors Colors, scale int) *image.Paletted {
+	min, max := world.Bounds()
+	return world.image(colors, scale, min, max)
+}
+
+func (world *World) image(colors Colors, scale int, min Point, max Point) *image.Paletted {
+	if scale < 1 {
+		scale = 1
+	}
+
+	tiles := make([]int, 0, len(colors))
+	for tile := range colors {
+		tiles = append(tiles, tile)
+	}
+	sort.Ints(tiles)
+	palette := color.Palette{color.Black}
+	indexes := make(map[int]uint8, len(tiles))
+	for _, tile := range tiles {
+		indexes[tile] = uint8(len(palette))
+		palette = append(palette, colors[tile])
+	}
+
+	width, height := (max.X-min.X+1)*scale, (max.Y-min.Y+1)*scale
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	for point, tile := range world.tiles {
+		if point.X < min.X || point.X > max.X || point.Y < min.Y || point.Y > max.Y {
+			continue
+		}
+		for y := 0; y < scale; y++ {
+			for x := 0; x < scale; x++ {
+				img.SetColorIndex((point.X-min.X)*scale+x, (point.Y-min.Y)*scale+y, indexes[tile])
+			}
+		}
+	}
+	return img
+}
+
+// Writes the world as PNG image, see World.Image.
+func (world *World) WritePNG(writer io.Writer, colors Colors, scale int) error {
+	return png.Encode(writer, world.Image(colors, scale))
+}
+
+// Collects frames of a world while a program runs to export them as animated GIF.
+type Recording struct {
+	Colors Colors
+	Scale  int
+	// Time every frame is shown, in 100ths of a second.
+	Delay int
+
+	frames []*World
+}
+
+// Adds the current state of the world as frame.
+func (recording *Recording) Capture(world *World) {
+	recording.frames = append(recording.frames, world.Clone())
+}
+
+func (recording *Recording) Len() int {
+	return len(recording.frames)
+}
+
+// Writes all frames as animated GIF. Every frame covers the bounds of all frames together so the image does
+// not jump around as the world grows.
+func (recording *Recording) WriteGIF(writer io.Writer) error {
+	var min, max Point
+	for i, frame := range recording.frames {
+		frameMin, frameMax := frame.Bounds()
+		if i == 0 {
+			min, max = frameMin, frameMax
+			continue
+		}
+		min = Point{minInt(min.X, frameMin.X), minInt(min.Y, frameMin.Y)}
+		max = Point{maxInt(max.X, frameMax.X), maxInt(max.Y, frameMax.Y)}
+	}
+
+	animation := gif.GIF{}
+	for _, frame := range recording.frames {
+		animation.Image = append(animation.Image, frame.image(recording.Colors, recording.Scale, min, max))
+		animation.Delay = append(animation.Delay, recording.Delay)
+	}
+	return gif.EncodeAll(writer, &animation)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,59 @@
+package world
+
+import "github.com/j6s/adventofcode/2019/intcode"
+
+// Colors of the hull panels.
+const (
+	Black = 0
+	White = 1
+)
+
+var HullPalette = Palette{Black: ' ', White: '#'}
+
+// Robot controlled by a program: it is fed the color of the panel it stands on and outputs the color to paint
+// the panel followed by the direction to turn, 0 for left and 1 for right, before moving forward by one panel.
+type Robot struct {
+	World     *World
+	Position  Point
+	Direction Point
+	// Every panel that was painted at least once.
+	Painted map[Point]bool
+}
+
+func NewRobot() *Robot {
+	return &Robot{World: NewWorld(), Direction: Up, Painted: make(map[Point]bool)}
+}
+
+// Runs the program until it halts. step is called after every move with the robot, it may be nil.
+func (robot *Robot) Run(intCode *intcode.IntCode, step func(robot *Robot)) error {
+	for {
+		intCode.Feed(robot.World.Get(robot.Position))
+		state, err := intCode.RunUntilBlocked()
+		if err != nil {
+			return err
+		}
+
+		outputs := intCode.TakeOutput()
+		for ; len(outputs) >= 2; outputs = outputs[2:] {
+			robot.World.Set(robot.Position, outputs[0])
+			robot.Painted[robot.Position] = true
+			robot.turn(outputs[1])
+			robot.Position = robot.Position.Add(robot.Direction)
+			if step != nil {
+				step(robot)
+			}
+		}
+
+		if state != intcode.WaitingForInput {
+			return nil
+		}
+	}
+}
+
+func (robot *Robot) turn(direction int) {
+	if direction == 0 {
+		robot.Direction = Point{robot.Direction.Y, -robot.Direction.X}
+	} else {
+		robot.Direction = Point{-robot.Direction.Y, robot.Direction.X}
+	}
+}
@@ -0,0 +1,87 @@
+package world
+
+import "github.com/j6s/adventofcode/2019/intcode"
+
+// Tiles of the arcade cabinet.
+const (
+	Empty = iota
+	Wall
+	Block
+	Paddle
+	Ball
+)
+
+var ArcadePalette = Palette{Empty: ' ', Wall: '#', Block: '=', Paddle: '-', Ball: 'o'}
+
+// The point a screen program draws to in order to show the score instead of a tile.
+var ScorePoint = Point{-1, 0}
+
+// Screen drawn by a program that outputs x, y and tile triples.
+type Screen struct {
+	World *World
+	Score int
+
+	// Outputs that do not make up a full triple yet.
+	partial []int
+}
+
+func NewScreen() *Screen {
+	return &Screen{World: NewWorld()}
+}
+
+// Draws the output values of a program, they may end in the middle of a triple.
+func (screen *Screen) Draw(values []int) {
+	values = append(screen.partial, values...)
+	for ; len(values) >= 3; values = values[3:] {
+		point := Point{values[0], values[1]}
+		if point == ScorePoint {
+			screen.Score = values[2]
+			continue
+		}
+		screen.World.Set(point, values[2])
+	}
+	screen.partial = append([]int(nil), values...)
+}
+
+// Runs the program until it halts, drawing its output. Whenever it waits for input, frame is called with the
+// screen drawn so far and returns the values to feed, e.g. the position of the joystick. frame is also called
+// once the program halted, its result is ignored then.
+func (screen *Screen) Run(intCode *intcode.IntCode, frame func(screen *Screen) []int) error {
+	for {
+		state, err := intCode.RunUntilBlocked()
+		screen.Draw(intCode.TakeOutput())
+		if err != nil {
+			return err
+		}
+
+		inputs := frame(screen)
+		if state != intcode.WaitingForInput {
+			return nil
+		}
+		if len(inputs) == 0 {
+			return intcode.ErrNoInput
+		}
+		intCode.Feed(inputs...)
+	}
+}
+
+// Moves the joystick towards the ball so the paddle never misses it.
+func (screen *Screen) FollowBall() int {
+	var ball, paddle Point
+	for _, point := range screen.World.Points() {
+		switch screen.World.Get(point) {
+		case Ball:
+			ball = point
+		case Paddle:
+			paddle = point
+		}
+	}
+
+	switch {
+	case ball.X < paddle.X:
+		return -1
+	case ball.X > paddle.X:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,27 @@
+package world
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Clears the terminal and moves the cursor to the top left corner.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// Redraws a world in a terminal while a program runs.
+type Terminal struct {
+	Writer  io.Writer
+	Palette Palette
+	// Pause after every frame so the changes can be followed.
+	Delay time.Duration
+}
+
+// Replaces the terminal contents with the world, followed by a status line.
+func (terminal *Terminal) Draw(world *World, status string) error {
+	_, err := fmt.Fprintf(terminal.Writer, "%s%s%s\n", clearScreen, world.Render(terminal.Palette), status)
+	if terminal.Delay > 0 {
+		time.Sleep(terminal.Delay)
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+// Package world keeps track of the 2D worlds that many intcode programs draw or explore, such as the screen of
+// an arcade cabinet or the hull a robot paints. Worlds can be rendered as text while the program runs and
+// exported to PNG and GIF.
+package world
+
+import (
+	"sort"
+	"strings"
+)
+
+type Point struct {
+	X int
+	Y int
+}
+
+func (point Point) Add(other Point) Point {
+	return Point{point.X + other.X, point.Y + other.Y}
+}
+
+// Directions with y growing downwards, like the rows of the screen.
+var (
+	Up    = Point{0, -1}
+	Right = Point{1, 0}
+	Down  = Point{0, 1}
+	Left  = Point{-1, 0}
+)
+
+// Sparse grid of tiles, every point that was never set holds tile 0.
+type World struct {
+	tiles map[Point]int
+}
+
+func NewWorld() *World {
+	return &World{tiles: make(map[Point]int)}
+}
+
+func (world *World) Get(point Point) int {
+	return world.tiles[point]
+}
+
+func (world *World) Set(point Point, tile int) {
+	world.tiles[point] = tile
+}
+
+// Number of points that were set, regardless of their tile.
+func (world *World) Len() int {
+	return len(world.tiles)
+}
+
+// Number of points holding the tile.
+func (world *World) Count(tile int) int {
+	count := 0
+	for _, value := range world.tiles {
+		if value == tile {
+			count++
+		}
+	}
+	return count
+}
+
+// Points that were set, ordered row by row.
+func (world *World) Points() []Point {
+	points := make([]Point, 0, len(world.tiles))
+	for point := range world.tiles {
+		points = append(points, point)
+	}
+	sort.Slice(points, func(a, b int) bool {
+		if points[a].Y != points[b].Y {
+			return points[a].Y < points[b].Y
+		}
+		return points[a].X < points[b].X
+	})
+	return points
+}
+
+// Smallest and largest coordinates of all points that were set, both inclusive.
+func (world *World) Bounds() (min Point, max Point) {
+	first := true
+	for point := range world.tiles {
+		if first {
+			min, max = point, point
+			first = false
+		}
+		if point.X < min.X {
+			min.X = point.X
+		}
+		if point.Y < min.Y {
+			min.Y = point.Y
+		}
+		if point.X > max.X {
+			max.X = point.X
+		}
+		if point.Y > max.Y {
+			max.Y = point.Y
+		}
+	}
+	return
+}
+
+func (world *World) Clone() *World {
+	clone := NewWorld()
+	for point, tile := range world.tiles {
+		clone.tiles[point] = tile
+	}
+	return clone
+}
+
+// Characters used to render tiles as text.
+type Palette map[int]rune
+
+// Renders everything within the bounds of the world as text, one line per row. Tiles missing from the palette
+// are shown as '?'.
+func (world *World) Render(palette Palette) string {
+	if len(world.tiles) == 0 {
+		return ""
+	}
+
+	min, max := world.Bounds()
+	builder := strings.Builder{}
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			character, known := palette[world.Get(Point{x, y})]
+			if !known {
+				character = '?'
+			}
+			builder.WriteRune(character)
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
@@ -0,0 +1,44 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/j6s/adventofcode/2019/intcode"
+)
+
+func TestScreenDrawsTriplesAcrossOutputs(t *testing.T) {
+	screen := NewScreen()
+	screen.Draw([]int{1, 2, 3, 6, 5})
+	screen.Draw([]int{4, -1, 0, 12345})
+
+	if screen.World.Get(Point{1, 2}) != Paddle || screen.World.Get(Point{6, 5}) != Ball || screen.Score != 12345 {
+		t.Errorf("Unexpected screen %v with score %d", screen.World.Points(), screen.Score)
+	}
+	if screen.World.Len() != 2 {
+		t.Errorf("Expected the score not to be drawn but got %v", screen.World.Points())
+	}
+}
+
+// Example from the puzzle description of the hull painting robot, with the program replaced by its outputs.
+func TestRobotPaintsExample(t *testing.T) {
+	outputs := []int{1, 0, 0, 0, 1, 0, 1, 0, 0, 1, 1, 0, 1, 0}
+	program := make([]int, 0)
+	for i := 0; i < len(outputs); i += 2 {
+		program = append(program, 3, 1000, 104, outputs[i], 104, outputs[i+1])
+	}
+	program = append(program, 99)
+	intCode, err := intcode.NewIntCode(intcode.FormatProgram(program))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	robot := NewRobot()
+	if err := robot.Run(&intCode, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "  #\n  #\n## \n"
+	if len(robot.Painted) != 6 || robot.World.Render(HullPalette) != expected {
+		t.Errorf("Expected 6 painted panels looking like\n%sbut got %d\n%s", expected, len(robot.Painted), robot.World.Render(HullPalette))
+	}
+}
@@ -38,7 +38,7 @@ func readInput() [][]bool {
 			} else if bit == '0' {
 				currentLine[i] = false
 			} else {
-				log.Fatalf("%s is not a valid bit value (must be 1 or 0)")
+				log.Fatalf("%c is not a valid bit value (must be 1 or 0)", bit)
 			}
 		}
 
@@ -1,6 +1,12 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -9,8 +15,93 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 )
 
+// Matches the folder of a solution, e.g. 2019/day-2-part-1 or 2021/day2-part1.
+var solutionPattern = regexp.MustCompile(`^(\d+)/day-?(\d+)-part-?(\d+)$`)
+
+// Timestamp the log package puts in front of every line.
+var logPrefixPattern = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} `)
+
+type solution struct {
+	file string
+	year int
+	day  int
+	part int
+}
+
+type result struct {
+	// stdout and stderr interleaved as they were written.
+	output     string
+	stdout     string
+	stderr     string
+	duration   time.Duration
+	exitStatus int
+	err        error
+}
+
+// Result of a solution in the JSON and CSV output.
+type report struct {
+	Year       int    `json:"year"`
+	Day        int    `json:"day"`
+	Part       int    `json:"part"`
+	Answer     string `json:"answer"`
+	DurationMs int64  `json:"durationMs"`
+	ExitStatus int    `json:"exitStatus"`
+	Stderr     string `json:"stderr"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Writer that can be shared by the stdout and stderr of a command, which are copied by separate goroutines.
+type lockedWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func (locked *lockedWriter) Write(data []byte) (int, error) {
+	locked.mutex.Lock()
+	defer locked.mutex.Unlock()
+	return locked.writer.Write(data)
+}
+
+func findSolutions() ([]solution, error) {
+	files, err := filepath.Glob("./**/*/main.go")
+	if err != nil {
+		return nil, err
+	}
+
+	solutions := make([]solution, 0, len(files))
+	for _, file := range files {
+		match := solutionPattern.FindStringSubmatch(filepath.ToSlash(path.Dir(file)))
+		if match == nil {
+			continue
+		}
+		year, _ := strconv.Atoi(match[1])
+		day, _ := strconv.Atoi(match[2])
+		part, _ := strconv.Atoi(match[3])
+		solutions = append(solutions, solution{file, year, day, part})
+	}
+
+	sort.Slice(solutions, func(a, b int) bool {
+		if solutions[a].year != solutions[b].year {
+			return solutions[a].year < solutions[b].year
+		}
+		if solutions[a].day != solutions[b].day {
+			return solutions[a].day < solutions[b].day
+		}
+		return solutions[a].part < solutions[b].part
+	})
+	return solutions, nil
+}
+
 func getInput(file string) (string, error) {
 	inputFile := path.Join(path.Dir(file), "input.txt")
 	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
@@ -25,41 +116,267 @@ func getInput(file string) (string, error) {
 	return string(contents), nil
 }
 
-func connectInputFileToStdin(cmd *exec.Cmd, file string) {
+// The answer of a solution is the last line it prints to stdout, or to stderr if it prints nothing to stdout,
+// without the timestamp of the log package.
+func getAnswer(result result) string {
+	output := result.stdout
+	if strings.TrimSpace(output) == "" {
+		output = result.stderr
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(logPrefixPattern.ReplaceAllString(lines[len(lines)-1], ""))
+}
+
+// Reads the expected answer from the answers file next to the solution.
+func getExpectedAnswer(file string) (answer string, found bool, err error) {
+	contents, err := ioutil.ReadFile(path.Join(path.Dir(file), "answers"))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSpace(string(contents)), true, nil
+}
+
+func connectInputFileToStdin(cmd *exec.Cmd, file string) error {
 	input, err := getInput(file)
 	if err != nil {
-		log.Fatalf("error running %s: %v", file, err)
+		return err
 	}
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
-		log.Fatalf("error running %s: %v", file, err)
+		return err
 	}
 
-	io.WriteString(stdin, input)
-	stdin.Close()
-
+	go func() {
+		io.WriteString(stdin, input)
+		stdin.Close()
+	}()
+	return nil
 }
 
-func run(file string) string {
-	cmd := exec.Command("go", "run", file)
-	connectInputFileToStdin(cmd, file)
+// Builds the solution into binDir and runs the binary, so that it can be killed once the timeout is reached.
+// `go run` would leave the compiled program running when killed.
+func run(solution solution, binDir string, timeout time.Duration) result {
+	binary := filepath.Join(binDir, fmt.Sprintf("%d-%d-%d", solution.year, solution.day, solution.part))
+	build := exec.Command("go", "build", "-o", binary, "./"+path.Dir(solution.file))
+	out, err := build.CombinedOutput()
+	if err != nil {
+		return result{output: string(out), stderr: string(out), exitStatus: -1, err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	out, err := cmd.CombinedOutput()
+	cmd := exec.CommandContext(ctx, binary)
+	err = connectInputFileToStdin(cmd, solution.file)
 	if err != nil {
-		log.Fatalf("error running %s: %v\n%s", file, err, out)
+		return result{exitStatus: -1, err: err}
+	}
+
+	var stdout, stderr, combined bytes.Buffer
+	shared := &lockedWriter{writer: &combined}
+	cmd.Stdout = io.MultiWriter(&stdout, shared)
+	cmd.Stderr = io.MultiWriter(&stderr, shared)
+
+	start := time.Now()
+	err = cmd.Run()
+	duration := time.Since(start)
+
+	exitStatus := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitStatus = exitErr.ExitCode()
+	} else if err != nil {
+		exitStatus = -1
 	}
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	return result{
+		output:     combined.String(),
+		stdout:     stdout.String(),
+		stderr:     stderr.String(),
+		duration:   duration,
+		exitStatus: exitStatus,
+		err:        err,
+	}
+}
+
+// Runs all solutions with the given number of workers. The returned channels are closed once the result of the
+// solution with the same index is available.
+func runAll(solutions []solution, binDir string, workers int, timeout time.Duration) ([]result, []chan struct{}) {
+	results := make([]result, len(solutions))
+	done := make([]chan struct{}, len(solutions))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	indexes := make(chan int)
+	go func() {
+		for i := range solutions {
+			indexes <- i
+		}
+		close(indexes)
+	}()
 
-	return string(out)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for index := range indexes {
+				results[index] = run(solutions[index], binDir, timeout)
+				close(done[index])
+			}
+		}()
+	}
+
+	return results, done
+}
+
+// Prints a table comparing the answer of every solution to its answers file. Returns false if any answer is
+// wrong or any solution failed, solutions without answers file do not count as failure.
+func verify(solutions []solution, results []result, done []chan struct{}) bool {
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "STATUS\tSOLUTION\tEXPECTED\tANSWER")
+
+	ok := true
+	for i, solution := range solutions {
+		<-done[i]
+		result := results[i]
+		expected, found, err := getExpectedAnswer(solution.file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		status, answer := "pass", getAnswer(result)
+		switch {
+		case result.err != nil:
+			status, answer = "fail", result.err.Error()
+			ok = false
+		case !found:
+			status, expected = "missing", "-"
+		case answer != expected:
+			status = "fail"
+			ok = false
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", status, path.Dir(solution.file), expected, answer)
+	}
+
+	table.Flush()
+	return ok
+}
+
+// Writes the results of all solutions as JSON array or CSV table, failed solutions have an error instead of an
+// answer. Returns false if any solution failed.
+func writeReports(format string, solutions []solution, results []result, done []chan struct{}) (bool, error) {
+	ok := true
+	reports := make([]report, len(solutions))
+	for i, solution := range solutions {
+		<-done[i]
+		result := results[i]
+		reports[i] = report{
+			Year:       solution.year,
+			Day:        solution.day,
+			Part:       solution.part,
+			DurationMs: result.duration.Milliseconds(),
+			ExitStatus: result.exitStatus,
+			Stderr:     result.stderr,
+		}
+		if result.err != nil {
+			reports[i].Error = result.err.Error()
+			ok = false
+		} else {
+			reports[i].Answer = getAnswer(result)
+		}
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return ok, encoder.Encode(reports)
+	}
+
+	writer := csv.NewWriter(os.Stdout)
+	writer.Write([]string{"year", "day", "part", "answer", "durationMs", "exitStatus", "stderr", "error"})
+	for _, report := range reports {
+		writer.Write([]string{
+			strconv.Itoa(report.Year),
+			strconv.Itoa(report.Day),
+			strconv.Itoa(report.Part),
+			report.Answer,
+			strconv.FormatInt(report.DurationMs, 10),
+			strconv.Itoa(report.ExitStatus),
+			report.Stderr,
+			report.Error,
+		})
+	}
+	writer.Flush()
+	return ok, writer.Error()
 }
 
 func main() {
-	files, err := filepath.Glob("./**/*/main.go")
+	workers := flag.Int("workers", runtime.NumCPU(), "number of solutions running at the same time")
+	timeout := flag.Duration("timeout", time.Minute, "time after which a solution is killed")
+	verifyAnswers := flag.Bool("verify", false, "compare the answers to the answers file of every solution")
+	format := flag.String("format", "text", "output format: text, json or csv")
+	flag.Parse()
+	if *format != "text" && *format != "json" && *format != "csv" {
+		log.Fatalf("Unknown format %s", *format)
+	}
+	if *verifyAnswers && *format != "text" {
+		log.Fatal("-verify only works with the text format")
+	}
+	if *workers < 1 {
+		log.Fatal("At least one worker is needed")
+	}
+
+	solutions, err := findSolutions()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	for _, file := range files {
-		fmt.Printf("%s: %s\n", file, run(file))
+	binDir, err := ioutil.TempDir("", "adventofcode")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(binDir)
+
+	results, done := runAll(solutions, binDir, *workers, *timeout)
+	if *verifyAnswers {
+		if !verify(solutions, results, done) {
+			os.RemoveAll(binDir)
+			os.Exit(1)
+		}
+		return
+	}
+	if *format != "text" {
+		ok, err := writeReports(*format, solutions, results, done)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !ok {
+			os.RemoveAll(binDir)
+			os.Exit(1)
+		}
+		return
+	}
+
+	failed := false
+	for i, solution := range solutions {
+		<-done[i]
+		result := results[i]
+		if result.err != nil {
+			failed = true
+			fmt.Printf("%s: error running: %v\n%s\n", solution.file, result.err, result.output)
+			continue
+		}
+		fmt.Printf("%s: %s\n", solution.file, result.output)
+	}
+
+	if failed {
+		os.RemoveAll(binDir)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,3 @@
+module github.com/j6s/adventofcode
+
+go 1.18